@@ -0,0 +1,122 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func handleBoard(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+	if interaction.GuildID == "" || interaction.Member == nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.guild_only"), "handleBoard() not in guild", nil)
+		return
+	}
+	if interaction.Member.Permissions&discordgo.PermissionManageGuild == 0 {
+		respondWithError(session, interaction.Interaction, tr(language, "error.missing_permission"), "handleBoard() missing permission", nil)
+		return
+	}
+
+	switch interaction.ApplicationCommandData().Options[0].Name {
+	case "set":
+		handleBoardSet(session, interaction)
+	case "remove":
+		handleBoardRemove(session, interaction)
+	}
+}
+
+// handleBoardSet posts a new board to the channel, replacing the board the
+// channel had before
+func handleBoardSet(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+
+	board := &TimerBoard{
+		Channel:  interaction.ChannelID,
+		Guild:    interaction.GuildID,
+		Scope:    ListScopeChannel,
+		Size:     defaultBoardSize,
+		Language: language,
+	}
+	for _, opt := range interaction.ApplicationCommandData().Options[0].Options {
+		switch opt.Name {
+		case "scope":
+			board.Scope = opt.StringValue()
+		case "count":
+			board.Size = int(opt.IntValue())
+		}
+	}
+
+	timers, err := boardTimers(session, board)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.getting_timers"), "handleBoardSet() getting timers", err)
+		return
+	}
+	message, err := session.ChannelMessageSendComplex(board.Channel, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{createBoardEmbed(board, timers, time.Now())},
+	})
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.posting_board"), "handleBoardSet() posting board", err)
+		return
+	}
+	board.MessageID = message.ID
+
+	old, err := getBoard(board.Channel)
+	if err == nil {
+		removeBoardMessage(session, old)
+	}
+	err = saveBoard(board)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.saving_board"), "handleBoardSet() saving board", err)
+		return
+	}
+
+	// Pinning needs the Manage Messages permission, the board works without
+	if err := session.ChannelMessagePin(board.Channel, board.MessageID); err != nil {
+		slog.Error("Pinning board", "error", err)
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: tr(language, "board.created"),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}, "handleBoardSet() success case")
+}
+
+func handleBoardRemove(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+
+	board, err := getBoard(interaction.ChannelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(session, interaction.Interaction, tr(language, "board.none"), "handleBoardRemove() no board", nil)
+		return
+	}
+	if err == nil {
+		err = deleteBoard(board.Channel)
+	}
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.saving_board"), "handleBoardRemove() deleting board", err)
+		return
+	}
+	removeBoardMessage(session, board)
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: tr(language, "board.removed"),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}, "handleBoardRemove() success case")
+}
+
+// removeBoardMessage deletes the message of a board, it may already be gone
+func removeBoardMessage(session *discordgo.Session, board *TimerBoard) {
+	err := session.ChannelMessageDelete(board.Channel, board.MessageID)
+	if err != nil && !isNotFound(err) {
+		slog.Error("Deleting board message", "error", err)
+	}
+}
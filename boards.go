@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const defaultBoardSize = 10
+
+// TimerBoard is a message the bot keeps edited with the next timers of its
+// channel or of the whole guild
+type TimerBoard struct {
+	Channel   string
+	Guild     string
+	MessageID string
+	// Scope is ListScopeChannel or ListScopeGuild
+	Scope string
+	// Size is how many timers the board shows
+	Size     int
+	Language string
+}
+
+var (
+	staleBoardChannels = make(map[string]bool)
+	staleBoardGuilds   = make(map[string]bool)
+	staleBoardsMutex   sync.Mutex
+)
+
+// markBoardsStale notes that the boards showing a timer have to be updated.
+// Timers without a stored guild could be on any guild board.
+func markBoardsStale(timer *Timer) {
+	staleBoardsMutex.Lock()
+	staleBoardChannels[timer.Channel] = true
+	staleBoardGuilds[timer.Guild] = true
+	staleBoardsMutex.Unlock()
+}
+
+// isStale reports whether the board shows timers of the changed channels or
+// guilds
+func (b *TimerBoard) isStale(channels map[string]bool, guilds map[string]bool) bool {
+	if b.Scope == ListScopeGuild {
+		return guilds[b.Guild] || guilds[""]
+	}
+	return channels[b.Channel]
+}
+
+// refreshStaleBoards updates the boards affected by changes since it last
+// ran, so that a burst of changes only edits each board once
+func refreshStaleBoards(session *discordgo.Session) {
+	staleBoardsMutex.Lock()
+	channels, guilds := staleBoardChannels, staleBoardGuilds
+	staleBoardChannels = make(map[string]bool)
+	staleBoardGuilds = make(map[string]bool)
+	staleBoardsMutex.Unlock()
+
+	if len(channels) == 0 && len(guilds) == 0 {
+		return
+	}
+
+	boards, err := getBoards()
+	if err != nil {
+		slog.Error("Getting boards", "error", err)
+		return
+	}
+	for _, board := range boards {
+		if board.isStale(channels, guilds) {
+			refreshBoard(session, board)
+		}
+	}
+}
+
+// boardTimers returns the next timers of a board, private timers are never
+// shown on it
+func boardTimers(session *discordgo.Session, board *TimerBoard) ([]*Timer, error) {
+	filter := TimerFilter{OnlyActive: true, HidePrivate: true}
+	if board.Scope == ListScopeGuild {
+		filter.Guild = board.Guild
+		filter.GuildChannels = guildChannelIDs(session, board.Guild)
+	} else {
+		filter.Channel = board.Channel
+	}
+
+	timers, err := listTimers(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(timers) > board.Size {
+		timers = timers[:board.Size]
+	}
+	return timers, nil
+}
+
+func createBoardEmbed(board *TimerBoard, timers []*Timer, now time.Time) *discordgo.MessageEmbed {
+	embed := createSharedTimerListEmbed(tr(board.Language, "board.title."+board.Scope), timers, board.Language)
+	if len(timers) == 0 {
+		embed.Description = tr(board.Language, "board.empty")
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: tr(board.Language, "board.updated")}
+	embed.Timestamp = now.Format(time.RFC3339)
+	return embed
+}
+
+func refreshBoard(session *discordgo.Session, board *TimerBoard) {
+	timers, err := boardTimers(session, board)
+	if err != nil {
+		slog.Error("Getting timers of board", "error", err)
+		return
+	}
+
+	embeds := []*discordgo.MessageEmbed{createBoardEmbed(board, timers, time.Now())}
+	_, err = session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:      board.MessageID,
+		Channel: board.Channel,
+		Embeds:  &embeds,
+	})
+	if isNotFound(err) {
+		// The message or its channel is gone, so is the board
+		if err := deleteBoard(board.Channel); err != nil {
+			slog.Error("Deleting board", "error", err)
+		}
+		return
+	}
+	if err != nil {
+		slog.Error("Updating board", "error", err)
+	}
+}
+
+func isNotFound(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardIsStale(t *testing.T) {
+	channelBoard := &TimerBoard{Channel: "c1", Guild: "g1", Scope: ListScopeChannel}
+	guildBoard := &TimerBoard{Channel: "c1", Guild: "g1", Scope: ListScopeGuild}
+
+	assert.True(t, channelBoard.isStale(map[string]bool{"c1": true}, map[string]bool{"g1": true}))
+	assert.False(t, channelBoard.isStale(map[string]bool{"c2": true}, map[string]bool{"g1": true}))
+
+	assert.True(t, guildBoard.isStale(map[string]bool{"c2": true}, map[string]bool{"g1": true}))
+	assert.False(t, guildBoard.isStale(map[string]bool{"c1": true}, map[string]bool{"g2": true}))
+	// Timers without a stored guild could be on any guild board
+	assert.True(t, guildBoard.isStale(map[string]bool{"c9": true}, map[string]bool{"": true}))
+}
+
+func TestCreateBoardEmbed(t *testing.T) {
+	board := &TimerBoard{Scope: ListScopeGuild, Language: LanguageEnglish}
+	now := time.Date(2030, time.May, 4, 18, 30, 0, 0, time.UTC)
+
+	embed := createBoardEmbed(board, nil, now)
+	assert.Equal(t, "Upcoming Timers on this Server", embed.Title)
+	assert.Equal(t, "There are no upcoming timers.", embed.Description)
+	assert.Equal(t, "2030-05-04T18:30:00Z", embed.Timestamp)
+
+	embed = createBoardEmbed(board, []*Timer{{ID: "a", Message: "Release", User: "1", SnoozedDue: now}}, now)
+	assert.Empty(t, embed.Description)
+	assert.Len(t, embed.Fields, 1)
+}
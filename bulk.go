@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	BulkDelete = "delete"
+	BulkSnooze = "snooze"
+	BulkShift  = "shift"
+)
+
+// pendingBulkOperation remembers the timers a bulk operation was previewed
+// with until the user confirms it, so that only those timers are changed
+type pendingBulkOperation struct {
+	kind     string
+	userID   string
+	timerIDs []string
+	// by is how far a snooze or shift moves the timers
+	by      time.Duration
+	expires time.Time
+}
+
+const pendingBulkOperationLifetime = 15 * time.Minute
+
+var (
+	pendingBulkOperations      = make(map[string]*pendingBulkOperation)
+	pendingBulkOperationsMutex sync.Mutex
+)
+
+// parseShift parses a duration like 1h30m that can be negative, e.g. -2h
+func parseShift(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if rest, ok := strings.CutPrefix(value, "-"); ok {
+		d, ok := parseCompactDuration(rest)
+		return -d, ok
+	}
+	return parseCompactDuration(strings.TrimPrefix(value, "+"))
+}
+
+// endOfDay returns the start of the day after t in the location of t
+func endOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+}
+
+// selectBulkTimers lists the timers matched by filter, without the timers
+// of pomodoro phases, which belong to their session
+func selectBulkTimers(filter TimerFilter) ([]*Timer, error) {
+	timers, err := listTimers(filter)
+	if err != nil {
+		return nil, err
+	}
+	selected := timers[:0]
+	for _, timer := range timers {
+		if timer.PomodoroID == "" {
+			selected = append(selected, timer)
+		}
+	}
+	return selected, nil
+}
+
+func handleTimerBulkDelete(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+
+	filter := TimerFilter{User: getUserFromInteraction(interaction).ID}
+	for _, opt := range interaction.ApplicationCommandData().Options[0].Options {
+		switch opt.Name {
+		case "query":
+			filter.Query = opt.StringValue()
+		case "tag":
+			filter.Tag = normalizeTag(opt.StringValue())
+		case "expired":
+			filter.OnlyExpired = opt.BoolValue()
+		}
+	}
+	// Without any filter all timers would match, /timer clear-expired and
+	// /timer delete are there for that
+	if filter.Query == "" && filter.Tag == "" && !filter.OnlyExpired {
+		respondWithError(session, interaction.Interaction, tr(language, "bulk.no_filter"), "handleTimerBulkDelete() no filter", nil)
+		return
+	}
+
+	timers, err := selectBulkTimers(filter)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.getting_timers"), "handleTimerBulkDelete() getting timers", err)
+		return
+	}
+	previewBulkOperation(session, interaction, &pendingBulkOperation{kind: BulkDelete}, timers)
+}
+
+// handleTimerBulkSnooze snoozes the active timers due until the end of today
+// in the user's timezone
+func handleTimerBulkSnooze(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+
+	userID := getUserFromInteraction(interaction).ID
+	today := time.Now().In(getQuietSettings(userID, interaction.GuildID).Location)
+	filter := TimerFilter{User: userID, OnlyActive: true, DueBefore: endOfDay(today)}
+	var by time.Duration
+	for _, opt := range interaction.ApplicationCommandData().Options[0].Options {
+		switch opt.Name {
+		case "by":
+			var ok bool
+			by, ok = parseCompactDuration(opt.StringValue())
+			if !ok {
+				respondWithError(session, interaction.Interaction, tr(language, "error.invalid_duration", opt.StringValue()), "handleTimerBulkSnooze() invalid duration", nil)
+				return
+			}
+		case "tag":
+			filter.Tag = normalizeTag(opt.StringValue())
+		}
+	}
+
+	timers, err := selectBulkTimers(filter)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.getting_timers"), "handleTimerBulkSnooze() getting timers", err)
+		return
+	}
+	previewBulkOperation(session, interaction, &pendingBulkOperation{kind: BulkSnooze, by: by}, timers)
+}
+
+// handleTimerBulkShift moves the active timers of a channel, by default the
+// current one, without counting it as a snooze
+func handleTimerBulkShift(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+
+	filter := TimerFilter{User: getUserFromInteraction(interaction).ID, Channel: interaction.ChannelID, OnlyActive: true}
+	var by time.Duration
+	for _, opt := range interaction.ApplicationCommandData().Options[0].Options {
+		switch opt.Name {
+		case "by":
+			var ok bool
+			by, ok = parseShift(opt.StringValue())
+			if !ok {
+				respondWithError(session, interaction.Interaction, tr(language, "error.invalid_duration", opt.StringValue()), "handleTimerBulkShift() invalid duration", nil)
+				return
+			}
+		case "channel":
+			filter.Channel = fmt.Sprint(opt.Value)
+		case "tag":
+			filter.Tag = normalizeTag(opt.StringValue())
+		}
+	}
+
+	timers, err := selectBulkTimers(filter)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.getting_timers"), "handleTimerBulkShift() getting timers", err)
+		return
+	}
+	previewBulkOperation(session, interaction, &pendingBulkOperation{kind: BulkShift, by: by}, timers)
+}
+
+// previewBulkOperation shows the timers an operation would change and asks
+// the user to confirm it
+func previewBulkOperation(session *discordgo.Session, interaction *discordgo.InteractionCreate, pending *pendingBulkOperation, timers []*Timer) {
+	language := getInteractionLanguage(interaction)
+
+	if len(timers) == 0 {
+		respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: tr(language, "bulk.none"),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		}, "previewBulkOperation() no timers")
+		return
+	}
+
+	token := randomString(12)
+	pending.userID = getUserFromInteraction(interaction).ID
+	pending.expires = time.Now().Add(pendingBulkOperationLifetime)
+	for _, timer := range timers {
+		pending.timerIDs = append(pending.timerIDs, timer.ID)
+	}
+
+	pendingBulkOperationsMutex.Lock()
+	for key, other := range pendingBulkOperations {
+		if time.Now().After(other.expires) {
+			delete(pendingBulkOperations, key)
+		}
+	}
+	pendingBulkOperations[token] = pending
+	pendingBulkOperationsMutex.Unlock()
+
+	confirmStyle := discordgo.PrimaryButton
+	if pending.kind == BulkDelete {
+		confirmStyle = discordgo.DangerButton
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: describeBulkOperation(pending, language),
+			Embeds:  []*discordgo.MessageEmbed{createTimerListEmbed(tr(language, "bulk.affected"), timers, language)},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label:    tr(language, "bulk.button.confirm"),
+							Style:    confirmStyle,
+							CustomID: "timer_bulk:confirm:" + token,
+						},
+						discordgo.Button{
+							Label:    tr(language, "bulk.button.cancel"),
+							Style:    discordgo.SecondaryButton,
+							CustomID: "timer_bulk:cancel:" + token,
+						},
+					},
+				},
+			},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}, "previewBulkOperation()")
+}
+
+// describeBulkOperation asks whether the previewed operation should be done
+func describeBulkOperation(pending *pendingBulkOperation, language string) string {
+	count := len(pending.timerIDs)
+	switch pending.kind {
+	case BulkSnooze:
+		return tr(language, "bulk.confirm.snooze", count, humanizeDelay(pending.by, language))
+	case BulkShift:
+		if pending.by < 0 {
+			return tr(language, "bulk.confirm.shift_earlier", count, humanizeDelay(-pending.by, language))
+		}
+		return tr(language, "bulk.confirm.shift_later", count, humanizeDelay(pending.by, language))
+	}
+	return tr(language, "bulk.confirm.delete", count)
+}
+
+// handleTimerBulkButton handles the confirm and cancel buttons, argument is
+// the action and the token of the operation like "confirm:abcd"
+func handleTimerBulkButton(session *discordgo.Session, interaction *discordgo.InteractionCreate, argument string) {
+	language := getInteractionLanguage(interaction)
+	action, token, _ := strings.Cut(argument, ":")
+	user := getUserFromInteraction(interaction)
+
+	pendingBulkOperationsMutex.Lock()
+	pending, ok := pendingBulkOperations[token]
+	if ok && pending.userID == user.ID {
+		delete(pendingBulkOperations, token)
+	}
+	pendingBulkOperationsMutex.Unlock()
+
+	if !ok || time.Now().After(pending.expires) {
+		respondWithError(session, interaction.Interaction, tr(language, "bulk.expired"), "handleTimerBulkButton() expired", nil)
+		return
+	}
+	if pending.userID != user.ID {
+		respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: tr(language, "bulk.not_yours"),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		}, "handleTimerBulkButton() not yours")
+		return
+	}
+
+	content := tr(language, "bulk.cancelled")
+	if action == "confirm" {
+		done, skipped := applyBulkOperation(pending)
+		content = tr(language, "bulk.done."+pending.kind, done)
+		if pending.kind == BulkDelete && done > 0 {
+			content += " " + tr(language, "bulk.restorable", humanizeDelay(config.Retention.DeletedTimers, language))
+		}
+		if skipped > 0 {
+			content += "\n" + tr(language, "bulk.skipped", skipped)
+		}
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
+		},
+	}, "handleTimerBulkButton() success case")
+}
+
+// applyBulkOperation changes the previewed timers one by one. Timers that
+// were deleted, fired or reached their snooze limit since the preview are
+// skipped.
+func applyBulkOperation(pending *pendingBulkOperation) (done int, skipped int) {
+	for _, timerID := range pending.timerIDs {
+		timer, err := getTimerByID(timerID)
+		if err != nil || timer.User != pending.userID || pending.kind != BulkDelete && timer.Shown {
+			skipped++
+			continue
+		}
+
+		switch pending.kind {
+		case BulkDelete:
+			err = deleteTimer(timer.ID)
+			if err == nil {
+				recordTimerEvent(timer, TimerEventDeleted, pending.userID, nil)
+			}
+		case BulkSnooze:
+			if limit := getSnoozeLimit(timer); limit > 0 && timer.SnoozeCount >= limit {
+				skipped++
+				continue
+			}
+			due := timer.SnoozedDue.Add(pending.by)
+			err = snoozeTimer(timer.ID, due, pending.userID)
+			if err == nil {
+				recordTimerEvent(timer, TimerEventSnoozed, pending.userID, map[string]string{"oldDue": unixDetail(timer.SnoozedDue), "newDue": unixDetail(due)})
+			}
+		case BulkShift:
+			shifted := *timer
+			shifted.Due = timer.Due.Add(pending.by)
+			shifted.SnoozedDue = timer.SnoozedDue.Add(pending.by)
+			err = updateTimer(&shifted)
+			if err == nil {
+				recordTimerEvent(timer, TimerEventEdited, pending.userID, editDetails(timer, &shifted))
+			}
+		}
+		if err != nil {
+			slog.Error("Applying bulk operation", "kind", pending.kind, "timer", timer.ID, "error", err)
+			skipped++
+			continue
+		}
+		done++
+	}
+	return done, skipped
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShift(t *testing.T) {
+	for input, expected := range map[string]time.Duration{
+		"2h":     2 * time.Hour,
+		"+1h30m": 90 * time.Minute,
+		"-1d":    -24 * time.Hour,
+		" -30m ": -30 * time.Minute,
+	} {
+		shift, ok := parseShift(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, expected, shift, input)
+	}
+
+	for _, input := range []string{"", "-", "tomorrow", "--1h"} {
+		_, ok := parseShift(input)
+		assert.False(t, ok, input)
+	}
+}
+
+func TestEndOfDay(t *testing.T) {
+	assert.Equal(t, calendarDay(2030, time.January, 1), endOfDay(time.Date(2029, time.December, 31, 23, 59, 0, 0, time.Local)))
+	assert.Equal(t, calendarDay(2030, time.March, 2), endOfDay(calendarDay(2030, time.March, 1)))
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	// 20:00 UTC is already the next morning in Tokyo
+	evening := time.Date(2030, time.March, 1, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2030, time.March, 3, 0, 0, 0, 0, tokyo), endOfDay(evening.In(tokyo)))
+}
+
+func TestDescribeBulkOperation(t *testing.T) {
+	pending := &pendingBulkOperation{kind: BulkDelete, timerIDs: []string{"a", "b"}}
+	assert.Equal(t, "Delete 2 timers?", describeBulkOperation(pending, LanguageEnglish))
+
+	pending.kind = BulkSnooze
+	pending.by = time.Hour
+	assert.Equal(t, "Snooze 2 timers by 1 hour?", describeBulkOperation(pending, LanguageEnglish))
+
+	pending.kind = BulkShift
+	pending.by = -2 * time.Hour
+	assert.Equal(t, "Move 2 timers 2 hours earlier?", describeBulkOperation(pending, LanguageEnglish))
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BusinessCalendar decides which days count as working days
+type BusinessCalendar struct {
+	// Weekend are the days of the week that are never working days
+	Weekend []time.Weekday
+	// Countries are the codes of holidayRules whose public holidays apply
+	Countries []string
+	// CustomHolidays are extra days off, either "2006-01-02" for a single day
+	// or "01-02" for a day repeating every year
+	CustomHolidays []string
+}
+
+func defaultBusinessCalendar() BusinessCalendar {
+	return BusinessCalendar{Weekend: []time.Weekday{time.Saturday, time.Sunday}}
+}
+
+// holidayRules returns the nationwide public holidays of a country in a year
+var holidayRules = map[string]func(year int) []time.Time{
+	"at": func(year int) []time.Time {
+		easter := easterSunday(year)
+		return []time.Time{
+			calendarDay(year, time.January, 1),
+			calendarDay(year, time.January, 6),
+			easter.AddDate(0, 0, 1),
+			calendarDay(year, time.May, 1),
+			easter.AddDate(0, 0, 39),
+			easter.AddDate(0, 0, 50),
+			easter.AddDate(0, 0, 60),
+			calendarDay(year, time.August, 15),
+			calendarDay(year, time.October, 26),
+			calendarDay(year, time.November, 1),
+			calendarDay(year, time.December, 8),
+			calendarDay(year, time.December, 25),
+			calendarDay(year, time.December, 26),
+		}
+	},
+	"de": func(year int) []time.Time {
+		easter := easterSunday(year)
+		return []time.Time{
+			calendarDay(year, time.January, 1),
+			easter.AddDate(0, 0, -2),
+			easter.AddDate(0, 0, 1),
+			calendarDay(year, time.May, 1),
+			easter.AddDate(0, 0, 39),
+			easter.AddDate(0, 0, 50),
+			calendarDay(year, time.October, 3),
+			calendarDay(year, time.December, 25),
+			calendarDay(year, time.December, 26),
+		}
+	},
+	"gb": func(year int) []time.Time {
+		easter := easterSunday(year)
+		return substituteWeekendHolidays([]time.Time{
+			calendarDay(year, time.January, 1),
+			easter.AddDate(0, 0, -2),
+			easter.AddDate(0, 0, 1),
+			nthWeekday(year, time.May, time.Monday, 1),
+			nthWeekday(year, time.May, time.Monday, -1),
+			nthWeekday(year, time.August, time.Monday, -1),
+			calendarDay(year, time.December, 25),
+			calendarDay(year, time.December, 26),
+		})
+	},
+	"us": func(year int) []time.Time {
+		holidays := []time.Time{
+			calendarDay(year, time.January, 1),
+			nthWeekday(year, time.January, time.Monday, 3),
+			nthWeekday(year, time.February, time.Monday, 3),
+			nthWeekday(year, time.May, time.Monday, -1),
+			calendarDay(year, time.June, 19),
+			calendarDay(year, time.July, 4),
+			nthWeekday(year, time.September, time.Monday, 1),
+			nthWeekday(year, time.October, time.Monday, 2),
+			calendarDay(year, time.November, 11),
+			nthWeekday(year, time.November, time.Thursday, 4),
+			calendarDay(year, time.December, 25),
+		}
+		// Holidays on a weekend are observed on the closest weekday
+		for i, holiday := range holidays {
+			switch holiday.Weekday() {
+			case time.Saturday:
+				holidays[i] = holiday.AddDate(0, 0, -1)
+			case time.Sunday:
+				holidays[i] = holiday.AddDate(0, 0, 1)
+			}
+		}
+		return holidays
+	},
+}
+
+// calendarDay returns the start of a day in local time
+func calendarDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// easterSunday uses the anonymous Gregorian algorithm
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	n := h + l - 7*m + 114
+	return calendarDay(year, time.Month(n/31), n%31+1)
+}
+
+// nthWeekday returns the n-th weekday of a month, or the last one for n = -1
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := calendarDay(year, month+1, 0)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+	}
+	first := calendarDay(year, month, 1)
+	return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+// substituteWeekendHolidays moves holidays on a weekend to the next weekday
+// that isn't already a holiday, as done in the UK
+func substituteWeekendHolidays(holidays []time.Time) []time.Time {
+	taken := make(map[time.Time]bool)
+	for _, holiday := range holidays {
+		taken[holiday] = true
+	}
+
+	result := make([]time.Time, 0, len(holidays))
+	for _, holiday := range holidays {
+		if holiday.Weekday() != time.Saturday && holiday.Weekday() != time.Sunday {
+			result = append(result, holiday)
+			continue
+		}
+		substitute := holiday
+		for substitute.Weekday() == time.Saturday || substitute.Weekday() == time.Sunday || taken[substitute] {
+			substitute = substitute.AddDate(0, 0, 1)
+		}
+		taken[substitute] = true
+		result = append(result, substitute)
+	}
+	return result
+}
+
+// IsHoliday reports whether day is a public or custom holiday
+func (c BusinessCalendar) IsHoliday(day time.Time) bool {
+	day = calendarDay(day.Year(), day.Month(), day.Day())
+
+	for _, country := range c.Countries {
+		rule, ok := holidayRules[country]
+		if !ok {
+			continue
+		}
+		// A holiday early in the next year can be observed on the last day
+		// of this one, e.g. New Year's Day on a Saturday in the US
+		for _, year := range []int{day.Year(), day.Year() + 1} {
+			for _, holiday := range rule(year) {
+				if holiday.Equal(day) {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, custom := range c.CustomHolidays {
+		if custom == day.Format("2006-01-02") || custom == day.Format("01-02") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBusinessDay reports whether day is neither on the weekend nor a holiday
+func (c BusinessCalendar) IsBusinessDay(day time.Time) bool {
+	return !slices.Contains(c.Weekend, day.Weekday()) && !c.IsHoliday(day)
+}
+
+// AddBusinessDays moves from forward by n working days, keeping the time of day
+func (c BusinessCalendar) AddBusinessDays(from time.Time, n int) time.Time {
+	result := from
+	// A calendar without any working days would never finish
+	for i := 0; n > 0 && i < 3660; i++ {
+		result = result.AddDate(0, 0, 1)
+		if c.IsBusinessDay(result) {
+			n--
+		}
+	}
+	return result
+}
+
+// LastBusinessDayOfMonth returns the last working day in the month of day
+func (c BusinessCalendar) LastBusinessDayOfMonth(day time.Time) time.Time {
+	last := calendarDay(day.Year(), day.Month()+1, 0)
+	for last.Day() > 1 && !c.IsBusinessDay(last) {
+		last = last.AddDate(0, 0, -1)
+	}
+	return last
+}
+
+// CountBusinessDays counts the working days after the day of from up to and
+// including the day of to, so that it is the inverse of AddBusinessDays
+func (c BusinessCalendar) CountBusinessDays(from time.Time, to time.Time) int {
+	day := calendarDay(from.Year(), from.Month(), from.Day())
+	end := calendarDay(to.Year(), to.Month(), to.Day())
+
+	count := 0
+	for day.Before(end) {
+		day = day.AddDate(0, 0, 1)
+		if c.IsBusinessDay(day) {
+			count++
+		}
+	}
+	return count
+}
+
+var weekdayAbbreviations = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+	"so":  time.Sunday,
+	"mo":  time.Monday,
+	"di":  time.Tuesday,
+	"mi":  time.Wednesday,
+	"do":  time.Thursday,
+	"fr":  time.Friday,
+	"sa":  time.Saturday,
+}
+
+// parseWeekend parses a list of weekdays like "sat, sun" or "Freitag,Samstag",
+// "none" stands for a week without weekend
+func parseWeekend(value string) ([]time.Weekday, error) {
+	var weekend []time.Weekday
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "none" {
+			continue
+		}
+		weekday, ok := weekdayNames[name]
+		if !ok {
+			weekday, ok = weekdayAbbreviations[name]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q, expected something like sat,sun", name)
+		}
+		if !slices.Contains(weekend, weekday) {
+			weekend = append(weekend, weekday)
+		}
+	}
+	if len(weekend) == 7 {
+		return nil, fmt.Errorf("at least one day of the week has to be a working day")
+	}
+	slices.Sort(weekend)
+	return weekend, nil
+}
+
+// formatWeekend is the inverse of parseWeekend
+func formatWeekend(weekend []time.Weekday) string {
+	names := make([]string, len(weekend))
+	for i, weekday := range weekend {
+		names[i] = strings.ToLower(weekday.String()[:3])
+	}
+	return strings.Join(names, ",")
+}
+
+var customHolidayPattern = regexp.MustCompile(`^(?:\d{4}-)?\d{2}-\d{2}$`)
+
+// parseHolidays parses a list of country codes and dates like
+// "de, 2030-12-24, 12-31" into the countries and custom holidays
+func parseHolidays(value string) (countries []string, custom []string, err error) {
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case customHolidayPattern.MatchString(entry):
+			full := entry
+			if len(entry) == 5 {
+				// 2000 is a leap year, so that 02-29 is accepted
+				full = "2000-" + entry
+			}
+			if _, err := time.Parse("2006-01-02", full); err != nil {
+				return nil, nil, fmt.Errorf("invalid date %q", entry)
+			}
+			if !slices.Contains(custom, entry) {
+				custom = append(custom, entry)
+			}
+		default:
+			if _, ok := holidayRules[entry]; !ok {
+				return nil, nil, fmt.Errorf("unknown holiday calendar %q, known are %s", entry, strings.Join(slices.Sorted(maps.Keys(holidayRules)), ", "))
+			}
+			if !slices.Contains(countries, entry) {
+				countries = append(countries, entry)
+			}
+		}
+	}
+	return countries, custom, nil
+}
+
+// formatHolidays is the inverse of parseHolidays
+func formatHolidays(countries []string, custom []string) string {
+	return strings.Join(append(slices.Clone(countries), custom...), ",")
+}
+
+var (
+	businessDaysPattern    = regexp.MustCompile(`(?i)^\s*(?:in\s+)?(\d+|an?|one|einem)\s+(?:business|working|work)\s*days?\s*$|^\s*in\s+(\d+|einem)\s+(?:werktag(?:en)?|arbeitstag(?:en)?)\s*$`)
+	nextBusinessDayPattern = regexp.MustCompile(`(?i)^\s*(?:next|the next)\s+(?:business|working|work)\s*day\s*$|^\s*(?:nächste[nr]?|am nächsten)\s+(?:werktag|arbeitstag)\s*$`)
+	lastBusinessDayPattern = regexp.MustCompile(`(?i)^\s*(?:on\s+)?(?:the\s+)?last\s+(?:business|working|work)\s*day\s+of\s+(?:the|this)?\s*(next\s+)?month\s*$|^\s*(?:am\s+)?letzte[nr]?\s+(?:werktag|arbeitstag)\s+(?:des|im)\s+(nächsten\s+)?monats?\s*$`)
+)
+
+// parseBusinessDays handles "in 3 business days", "next workday" and "last
+// workday of the month". Like other day offsets the time of day is left for
+// the default time to fill in.
+func parseBusinessDays(timeStr string, calendar BusinessCalendar, now time.Time) (time.Time, bool) {
+	if match := businessDaysPattern.FindStringSubmatch(timeStr); match != nil {
+		count := cmp.Or(match[1], match[2])
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			// "a", "an", "one" and "einem" all mean one day
+			n = 1
+		}
+		return calendar.AddBusinessDays(now, n), true
+	}
+
+	if nextBusinessDayPattern.MatchString(timeStr) {
+		return calendar.AddBusinessDays(now, 1), true
+	}
+
+	if match := lastBusinessDayPattern.FindStringSubmatch(timeStr); match != nil {
+		month := now
+		if match[1] != "" || match[2] != "" {
+			month = calendarDay(now.Year(), now.Month()+1, 1)
+		}
+		last := calendar.LastBusinessDayOfMonth(month)
+		// On the last working day itself, or after it, the next month is meant
+		if !last.After(calendarDay(now.Year(), now.Month(), now.Day())) {
+			last = calendar.LastBusinessDayOfMonth(calendarDay(now.Year(), now.Month()+1, 1))
+		}
+		return last, true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHolidayRules(t *testing.T) {
+	testCases := []struct {
+		country string
+		day     time.Time
+	}{
+		{country: "de", day: calendarDay(2030, time.April, 19)},  // Good Friday
+		{country: "de", day: calendarDay(2030, time.June, 10)},   // Whit Monday
+		{country: "de", day: calendarDay(2030, time.October, 3)}, // German Unity Day
+		{country: "at", day: calendarDay(2030, time.June, 20)},   // Corpus Christi
+		{country: "us", day: calendarDay(2030, time.November, 28)},
+		{country: "us", day: calendarDay(2026, time.July, 3)},      // July 4th observed on Friday
+		{country: "us", day: calendarDay(2021, time.December, 31)}, // New Year's Day 2022 observed on Friday
+		{country: "gb", day: calendarDay(2027, time.December, 28)},
+		{country: "gb", day: calendarDay(2030, time.August, 26)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.country+" "+tc.day.Format("2006-01-02"), func(t *testing.T) {
+			calendar := BusinessCalendar{Countries: []string{tc.country}}
+			assert.True(t, calendar.IsHoliday(tc.day))
+			assert.False(t, calendar.IsHoliday(tc.day.AddDate(0, 0, 2)))
+		})
+	}
+}
+
+func TestEasterSunday(t *testing.T) {
+	assert.Equal(t, calendarDay(2024, time.March, 31), easterSunday(2024))
+	assert.Equal(t, calendarDay(2025, time.April, 20), easterSunday(2025))
+	assert.Equal(t, calendarDay(2030, time.April, 21), easterSunday(2030))
+}
+
+func TestBusinessDayArithmetic(t *testing.T) {
+	calendar := defaultBusinessCalendar()
+	calendar.Countries = []string{"de"}
+	calendar.CustomHolidays = []string{"12-24"}
+
+	// Thursday before Easter 2030
+	thursday := time.Date(2030, time.April, 18, 10, 0, 0, 0, time.Local)
+
+	t.Run("skips weekends and holidays", func(t *testing.T) {
+		result := calendar.AddBusinessDays(thursday, 1)
+		assert.Equal(t, time.Date(2030, time.April, 23, 10, 0, 0, 0, time.Local), result)
+	})
+
+	t.Run("count is the inverse of add", func(t *testing.T) {
+		for n := 0; n < 30; n++ {
+			assert.Equal(t, n, calendar.CountBusinessDays(thursday, calendar.AddBusinessDays(thursday, n)))
+		}
+	})
+
+	t.Run("recurring custom holiday", func(t *testing.T) {
+		assert.False(t, calendar.IsBusinessDay(calendarDay(2031, time.December, 24)))
+	})
+
+	t.Run("last business day of month", func(t *testing.T) {
+		// August 31st 2030 is a Saturday
+		assert.Equal(t, calendarDay(2030, time.August, 30), calendar.LastBusinessDayOfMonth(calendarDay(2030, time.August, 5)))
+	})
+
+	t.Run("custom weekend", func(t *testing.T) {
+		custom := BusinessCalendar{Weekend: []time.Weekday{time.Friday, time.Saturday}}
+		assert.Equal(t, time.Date(2030, time.April, 21, 10, 0, 0, 0, time.Local), custom.AddBusinessDays(thursday, 1))
+	})
+}
+
+func TestParseBusinessDays(t *testing.T) {
+	calendar := defaultBusinessCalendar()
+	// A Friday
+	now := time.Date(2030, time.May, 17, 14, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		input    string
+		expected time.Time
+	}{
+		{input: "in 3 business days", expected: time.Date(2030, time.May, 22, 14, 0, 0, 0, time.Local)},
+		{input: "1 working day", expected: time.Date(2030, time.May, 20, 14, 0, 0, 0, time.Local)},
+		{input: "in 2 Werktagen", expected: time.Date(2030, time.May, 21, 14, 0, 0, 0, time.Local)},
+		{input: "next workday", expected: time.Date(2030, time.May, 20, 14, 0, 0, 0, time.Local)},
+		{input: "nächster Arbeitstag", expected: time.Date(2030, time.May, 20, 14, 0, 0, 0, time.Local)},
+		{input: "last workday of the month", expected: calendarDay(2030, time.May, 31)},
+		{input: "last business day of next month", expected: calendarDay(2030, time.June, 28)},
+		{input: "letzter Werktag des Monats", expected: calendarDay(2030, time.May, 31)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			result, ok := parseBusinessDays(tc.input, calendar, now)
+
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	t.Run("with time of day", func(t *testing.T) {
+		result, err := parseTime("in 3 business days at 9am")
+
+		require.NoError(t, err)
+		assert.Equal(t, 9, result.Hour())
+		assert.True(t, defaultBusinessCalendar().IsBusinessDay(result))
+	})
+}
+
+func TestParseWeekendAndHolidays(t *testing.T) {
+	weekend, err := parseWeekend("Freitag, sat")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Weekday{time.Friday, time.Saturday}, weekend)
+	assert.Equal(t, "fri,sat", formatWeekend(weekend))
+
+	_, err = parseWeekend("someday")
+	assert.Error(t, err)
+
+	countries, custom, err := parseHolidays("DE, 2030-12-24, 12-31, 02-29")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"de"}, countries)
+	assert.Equal(t, []string{"2030-12-24", "12-31", "02-29"}, custom)
+
+	for _, input := range []string{"xx", "2030-02-30", "13-01"} {
+		t.Run(input, func(t *testing.T) {
+			_, _, err := parseHolidays(input)
+			assert.Error(t, err)
+		})
+	}
+}
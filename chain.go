@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// FollowUp is a timer that is started once the previous link of a chain fires
+type FollowUp struct {
+	Message string        `json:"message"`
+	Delay   time.Duration `json:"delay"`
+}
+
+const maxFollowUps = 10
+
+// followUpSeparatorPattern splits "in 15 minutes: verify; 1h: announce" and
+// "15m: verify -> 1h: announce" into steps
+var followUpSeparatorPattern = regexp.MustCompile(`\s*(?:;|->|→)\s*`)
+
+// FollowUpTimeError is returned for a step whose time is not a duration. A
+// point in time like "tomorrow 9am" can't be counted from the previous step.
+type FollowUpTimeError struct {
+	Input string
+}
+
+func (e *FollowUpTimeError) Error() string {
+	return fmt.Sprintf("the time %q of a follow-up has to be a duration", e.Input)
+}
+
+// parseFollowUps parses the steps of a chain, each written as "<duration>: <message>".
+// The duration is stored as a delay, so that each step starts counting once
+// the previous one fired.
+func parseFollowUps(value string, options ParseOptions, now time.Time) ([]FollowUp, error) {
+	var followUps []FollowUp
+	for _, step := range followUpSeparatorPattern.Split(strings.TrimSpace(value), -1) {
+		if step == "" {
+			continue
+		}
+
+		// The time itself may contain colons like 1:30, so only ": " separates
+		timeStr, message, found := strings.Cut(step, ": ")
+		message = strings.TrimSpace(message)
+		if !found || message == "" {
+			return nil, fmt.Errorf("expected steps like \"15m: check the oven\", got %q", step)
+		}
+		if !isDuration(timeStr) {
+			return nil, &FollowUpTimeError{Input: strings.TrimSpace(timeStr)}
+		}
+
+		due, err := parseTimeWithOptions(timeStr, options)
+		if err != nil {
+			return nil, err
+		}
+		delay := due.Sub(now).Round(time.Second)
+		if delay <= 0 {
+			return nil, fmt.Errorf("the time %q of a follow-up has to be in the future", timeStr)
+		}
+
+		followUps = append(followUps, FollowUp{Message: message, Delay: delay})
+	}
+
+	if len(followUps) > maxFollowUps {
+		return nil, fmt.Errorf("a chain can have at most %d follow-ups", maxFollowUps)
+	}
+	return followUps, nil
+}
+
+// describeFollowUpError explains to the user why their follow-ups were rejected
+func describeFollowUpError(err error, language string) string {
+	var timeErr *FollowUpTimeError
+	if errors.As(err, &timeErr) {
+		return tr(language, "error.follow_up_time", timeErr.Input)
+	}
+	return tr(language, "error.invalid_follow_ups", err)
+}
+
+func encodeFollowUps(followUps []FollowUp) (string, error) {
+	if len(followUps) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(followUps)
+	return string(encoded), err
+}
+
+func decodeFollowUps(encoded string) ([]FollowUp, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	var followUps []FollowUp
+	err := json.Unmarshal([]byte(encoded), &followUps)
+	return followUps, err
+}
+
+// startFollowUp creates the next link of the chain of a timer that just fired
+func startFollowUp(session *discordgo.Session, timer *Timer, now time.Time) {
+	if len(timer.FollowUps) == 0 {
+		return
+	}
+
+	id, err := newTimerID()
+	if err != nil {
+		slog.Error("Creating follow-up timer ID", "error", err)
+		return
+	}
+
+	next := timer.FollowUps[0]
+	due := now.Add(next.Delay)
+	followUp := &Timer{
+		ID:            id,
+		Message:       next.Message,
+		User:          timer.User,
+		Channel:       timer.Channel,
+		Created:       now,
+		Due:           due,
+		SnoozedDue:    due,
+		Language:      timer.Language,
+		FollowUps:     timer.FollowUps[1:],
+		ChainID:       timer.ChainID,
+		ChainPosition: timer.ChainPosition + 1,
+		ChainLength:   timer.ChainLength,
+		Guild:         timer.Guild,
+		Private:       timer.Private,
+	}
+	if err := insertTimer(followUp); err != nil {
+		slog.Error("Creating follow-up timer", "error", err)
+		return
+	}
+	recordTimerEvent(followUp, TimerEventCreated, "", map[string]string{"due": unixDetail(due)})
+
+	// Without this a snoozed timer would start its follow-up a second time
+	if err := clearFollowUps(timer.ID); err != nil {
+		slog.Error("Clearing follow-ups", "error", err)
+	}
+
+	user, err := session.User(timer.User)
+	if err != nil {
+		slog.Error("Getting user", "error", err)
+		return
+	}
+	language := getTimerLanguage(timer)
+	_, err = session.ChannelMessageSendEmbed(timer.Channel, createTimerEmbed(followUp, user, TimerEmbedTypeFollowUp, language))
+	if err != nil {
+		slog.Error("Sending follow-up message", "error", err)
+	}
+}
+
+// describeFollowUps lists the remaining steps of a chain for the timer embed
+func describeFollowUps(followUps []FollowUp, language string) string {
+	lines := make([]string, len(followUps))
+	for i, followUp := range followUps {
+		lines[i] = tr(language, "chain.step", humanizeDelay(followUp.Delay, language), followUp.Message)
+	}
+	return strings.Join(lines, "\n")
+}
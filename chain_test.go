@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFollowUps(t *testing.T) {
+	now := time.Now()
+
+	t.Run("multiple steps", func(t *testing.T) {
+		followUps, err := parseFollowUps("15m: verify the deploy; in 1 hour: announce -> 1h30m: clean up", defaultParseOptions(), now)
+
+		require.NoError(t, err)
+		assert.Equal(t, []FollowUp{
+			{Message: "verify the deploy", Delay: 15 * time.Minute},
+			{Message: "announce", Delay: time.Hour},
+			{Message: "clean up", Delay: 90 * time.Minute},
+		}, followUps)
+	})
+
+	t.Run("message may contain colons", func(t *testing.T) {
+		followUps, err := parseFollowUps("5m: note: check the oven", defaultParseOptions(), now)
+
+		require.NoError(t, err)
+		require.Len(t, followUps, 1)
+		assert.Equal(t, "note: check the oven", followUps[0].Message)
+	})
+
+	t.Run("only durations", func(t *testing.T) {
+		_, err := parseFollowUps("15m: verify; tomorrow 9am: announce", defaultParseOptions(), now)
+
+		var timeErr *FollowUpTimeError
+		require.ErrorAs(t, err, &timeErr)
+		assert.Equal(t, "tomorrow 9am", timeErr.Input)
+		assert.Equal(t, "The time \"tomorrow 9am\" of a follow-up has to be a duration like \"15m\" or \"in 1 hour\", it counts from the previous step", describeFollowUpError(err, LanguageEnglish))
+	})
+
+	invalid := []string{
+		"check the oven",
+		"15m:",
+		"gibberish: check the oven",
+	}
+	for _, input := range invalid {
+		t.Run(input, func(t *testing.T) {
+			_, err := parseFollowUps(input, defaultParseOptions(), now)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEncodeFollowUps(t *testing.T) {
+	encoded, err := encodeFollowUps(nil)
+	require.NoError(t, err)
+	assert.Empty(t, encoded)
+
+	followUps := []FollowUp{{Message: "verify", Delay: 15 * time.Minute}, {Message: "announce", Delay: time.Hour}}
+	encoded, err = encodeFollowUps(followUps)
+	require.NoError(t, err)
+
+	decoded, err := decodeFollowUps(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, followUps, decoded)
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const commandUsage = `Usage: timer-bot [flags] [command]
+
+Without a command the bot connects to Discord and runs.
+
+Commands:
+  config print                       Print the effective configuration
+  timers list [--user ID] [--channel ID] [--due-before TIME] [--all]
+                                     List timers
+  timers delete ID...                Delete timers, they can be restored until
+                                     retention.deleted_timers has passed
+  timers reschedule ID TIME          Set a new due time and re-arm a timer
+  stats                              Show database statistics
+  cleanup                            Apply the retention policies now
+  vacuum                             Compact the database file
+`
+
+// runCommand executes an administrative command. Apart from config print
+// these operate directly on the database and never connect to Discord.
+func runCommand(cfg *Config, args []string) error {
+	switch args[0] {
+	case "config":
+		if len(args) != 2 || args[1] != "print" {
+			return errors.New("usage: timer-bot config print")
+		}
+		err := cfg.print(os.Stdout)
+		if err != nil {
+			return err
+		}
+		return cfg.validate()
+	case "help":
+		fmt.Print(commandUsage)
+		return nil
+	}
+
+	run, err := databaseCommand(cfg, args, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	err = cfg.apply()
+	if err != nil {
+		return err
+	}
+	config = cfg
+
+	err = openExistingDB(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	return run()
+}
+
+// databaseCommand looks up a command working on the database, so that
+// unknown commands are reported before the database is opened
+func databaseCommand(cfg *Config, args []string, w io.Writer) (func() error, error) {
+	switch args[0] {
+	case "timers":
+		if len(args) < 2 {
+			return nil, errors.New("usage: timer-bot timers list|delete|reschedule")
+		}
+		switch args[1] {
+		case "list":
+			return func() error { return runTimersList(w, args[2:]) }, nil
+		case "delete":
+			return func() error { return runTimersDelete(w, args[2:]) }, nil
+		case "reschedule":
+			return func() error { return runTimersReschedule(w, args[2:]) }, nil
+		}
+		return nil, fmt.Errorf("unknown timers command %q", args[1])
+	case "stats":
+		return func() error { return runStats(w) }, nil
+	case "cleanup":
+		return func() error { return runCleanup(w, cfg.Retention) }, nil
+	case "vacuum":
+		return func() error { return runVacuum(w, cfg.Database.Path) }, nil
+	}
+
+	return nil, fmt.Errorf("unknown command %q\n\n%s", args[0], commandUsage)
+}
+
+// openExistingDB opens the database for a command. Unlike the bot these
+// must not create an empty database when the path is mistyped.
+func openExistingDB(path string) error {
+	_, err := os.Stat(path)
+	if err == nil {
+		err = initDB(path)
+	}
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	return nil
+}
+
+func runTimersList(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("timer-bot timers list", flag.ContinueOnError)
+	user := fs.String("user", "", "Only list timers of this user ID")
+	channel := fs.String("channel", "", "Only list timers in this channel ID")
+	dueBefore := fs.String("due-before", "", "Only list timers due before this time")
+	all := fs.Bool("all", false, "Include timers that were already shown")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	filter := TimerFilter{
+		User:       *user,
+		Channel:    *channel,
+		OnlyActive: !*all,
+	}
+	if *dueBefore != "" {
+		filter.DueBefore, err = parseTime(*dueBefore)
+		if err != nil {
+			return fmt.Errorf("invalid --due-before: %w", err)
+		}
+	}
+
+	timers, err := listTimers(filter)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tUSER\tCHANNEL\tDUE\tSNOOZES\tSHOWN\tMESSAGE")
+	for _, timer := range timers {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%t\t%s\n",
+			timer.ID, timer.User, timer.Channel,
+			timer.SnoozedDue.In(time.Local).Format(time.DateTime),
+			timer.SnoozeCount, timer.Shown,
+			strings.ReplaceAll(timer.Message, "\n", " "))
+	}
+	err = tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%d timers\n", len(timers))
+	return nil
+}
+
+func runTimersDelete(w io.Writer, ids []string) error {
+	if len(ids) == 0 {
+		return errors.New("usage: timer-bot timers delete ID...")
+	}
+
+	var errs []error
+	for _, id := range ids {
+		timer, err := getTimerByID(id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = errors.New("not found")
+			}
+			errs = append(errs, fmt.Errorf("timer %s: %w", id, err))
+			continue
+		}
+
+		err = deleteTimer(id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("timer %s: %w", id, err))
+			continue
+		}
+		recordTimerEvent(timer, TimerEventDeleted, "", nil)
+		fmt.Fprintf(w, "Deleted timer %s, it can be restored with /timer restore for %s\n", id, humanizeDelay(config.Retention.DeletedTimers, LanguageEnglish))
+	}
+
+	return errors.Join(errs...)
+}
+
+func runTimersReschedule(w io.Writer, args []string) error {
+	if len(args) < 2 {
+		return errors.New("usage: timer-bot timers reschedule ID TIME")
+	}
+	id := args[0]
+
+	timer, err := getTimerByID(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("timer %s not found", id)
+		}
+		return err
+	}
+
+	due, err := parseTime(strings.Join(args[1:], " "))
+	if err != nil {
+		return fmt.Errorf("invalid time: %w", err)
+	}
+
+	err = rescheduleTimer(id, due)
+	if err != nil {
+		return err
+	}
+	recordTimerEvent(timer, TimerEventEdited, "", map[string]string{"oldDue": unixDetail(timer.SnoozedDue), "newDue": unixDetail(due)})
+
+	fmt.Fprintf(w, "Rescheduled timer %s to %s\n", id, due.Format(time.DateTime))
+	return nil
+}
+
+func runStats(w io.Writer) error {
+	stats, err := getTimerStats()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Timers:\t%d\n", stats.Total)
+	fmt.Fprintf(tw, "Active:\t%d\n", stats.Active)
+	fmt.Fprintf(tw, "Overdue:\t%d\n", stats.Overdue)
+	fmt.Fprintf(tw, "Users:\t%d\n", stats.Users)
+	fmt.Fprintf(tw, "Channels:\t%d\n", stats.Channels)
+	fmt.Fprintf(tw, "Snoozes:\t%d\n", stats.Snoozes)
+	return tw.Flush()
+}
+
+func runCleanup(w io.Writer, cfg RetentionConfig) error {
+	purged, removed, err := applyRetention(cfg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	action := "Removed"
+	if cfg.Archive {
+		action = "Archived"
+	}
+	fmt.Fprintf(w, "Purged %d deleted timers\n%s %d shown timers\n", purged, action, removed)
+	return nil
+}
+
+func runVacuum(w io.Writer, path string) error {
+	before, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	err = vacuumDB()
+	if err != nil {
+		return err
+	}
+
+	after, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Vacuumed %s: %d bytes -> %d bytes\n", path, before.Size(), after.Size())
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommandDoesNotCreateDatabase(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Database.Path = filepath.Join(t.TempDir(), "missing.db")
+
+	assert.NoError(t, runCommand(cfg, []string{"help"}))
+	assert.ErrorContains(t, runCommand(cfg, []string{"bogus"}), `unknown command "bogus"`)
+	assert.ErrorContains(t, runCommand(cfg, []string{"timers", "bogus"}), `unknown timers command "bogus"`)
+	assert.ErrorContains(t, runCommand(cfg, []string{"stats"}), "opening database")
+
+	_, err := os.Stat(cfg.Database.Path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRunTimersCommands(t *testing.T) {
+	require.NoError(t, initDB(filepath.Join(t.TempDir(), "timers.db")))
+	due := time.Now().Add(time.Hour)
+	_, err := createTimer("a", "Water\nthe plants", "u1", "c1", due, LanguageEnglish, TimerOptions{})
+	require.NoError(t, err)
+	_, err = createTimer("b", "Call back", "u2", "c1", due.Add(time.Hour), LanguageEnglish, TimerOptions{})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, runTimersList(&out, nil))
+	assert.Contains(t, out.String(), "Water the plants")
+	assert.Contains(t, out.String(), "2 timers\n")
+
+	out.Reset()
+	require.NoError(t, runTimersList(&out, []string{"--user", "u2"}))
+	assert.NotContains(t, out.String(), "Water")
+	assert.Contains(t, out.String(), "1 timers\n")
+
+	out.Reset()
+	require.NoError(t, runTimersReschedule(&out, []string{"b", "2030-01-02", "15:04"}))
+	assert.Equal(t, "Rescheduled timer b to 2030-01-02 15:04:00\n", out.String())
+	timer, err := getTimerByID("b")
+	require.NoError(t, err)
+	assert.True(t, timer.SnoozedDue.Equal(time.Date(2030, time.January, 2, 15, 4, 0, 0, time.Local)))
+	assert.Error(t, runTimersReschedule(&out, []string{"zz", "tomorrow"}))
+
+	out.Reset()
+	err = runTimersDelete(&out, []string{"a", "zz"})
+	assert.ErrorContains(t, err, "timer zz: not found")
+	assert.Equal(t, "Deleted timer a, it can be restored with /timer restore for 1 week\n", out.String())
+	_, err = getTimerByID("a")
+	assert.Error(t, err)
+
+	out.Reset()
+	require.NoError(t, runStats(&out))
+	assert.Regexp(t, `Timers:\s+1\n`, out.String())
+	assert.Regexp(t, `Active:\s+1\n`, out.String())
+}
+
+func TestRunVacuum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vacuum.db")
+	require.NoError(t, initDB(path))
+
+	var out bytes.Buffer
+	require.NoError(t, runVacuum(&out, path))
+	assert.Contains(t, out.String(), "Vacuumed "+path)
+}
@@ -1,10 +1,11 @@
 package main
 
 import (
-	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/dustin/go-humanize"
 )
 
 var commands = []*discordgo.ApplicationCommand{
@@ -13,10 +14,11 @@ var commands = []*discordgo.ApplicationCommand{
 		Description: "Calculate the time until a date",
 		Options: []*discordgo.ApplicationCommandOption{
 			{
-				Type:        discordgo.ApplicationCommandOptionString,
-				Name:        "date",
-				Description: "The date to calculate the time until",
-				Required:    true,
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "date",
+				Description:  "The date to calculate the time until",
+				Required:     true,
+				Autocomplete: true,
 			},
 		},
 	},
@@ -33,16 +35,84 @@ var commands = []*discordgo.ApplicationCommand{
 						Type:        discordgo.ApplicationCommandOptionString,
 						Name:        "message",
 						Description: "The message to display when the timer is up",
-						Required:    true,
+						Required:    false,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "time",
+						Description:  "The time to set the timer for",
+						Required:     false,
+						Autocomplete: true,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "template",
+						Description:  "A saved template, the other options override it",
+						Required:     false,
+						Autocomplete: true,
 					},
 					{
 						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        "time",
-						Description: "The time to set the timer for",
-						Required:    true,
+						Name:        "then",
+						Description: "Follow-up timers started one after another, e.g. 15m: verify; 1h: announce",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "max_snoozes",
+						Description: "How often the timer can be snoozed before it escalates or expires",
+						Required:    false,
+						MinValue:    &minSnoozeLimit,
+						MaxValue:    100,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "tags",
+						Description:  "Tags to organize the timer, e.g. work, billing",
+						Required:     false,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "important",
+						Description: "Ping again until someone acknowledges the timer",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "reping_every",
+						Description: "Minutes between the pings of an important timer, 15 by default",
+						Required:    false,
+						MinValue:    &minRepingInterval,
+						MaxValue:    24 * 60,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionMentionable,
+						Name:        "escalate_to",
+						Description: "Backup user or role pinged if nobody acknowledges the timer",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "escalate_after",
+						Description: "Minutes after the due time until the backup is pinged, 60 by default",
+						Required:    false,
+						MinValue:    &minRepingInterval,
+						MaxValue:    7 * 24 * 60,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "private",
+						Description: "Hide the timer when others list the timers of the channel or server",
+						Required:    false,
 					},
 				},
 			},
+			{
+				Name:        "new",
+				Description: "Create a new timer in a form",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
 			{
 				Name:        "list",
 				Description: "List all timers",
@@ -54,32 +124,76 @@ var commands = []*discordgo.ApplicationCommand{
 						Description: "Whether to show expired timers",
 						Required:    false,
 					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "tag",
+						Description:  "Only list timers with this tag",
+						Required:     false,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "scope",
+						Description: "Whose timers to list, your own by default",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Mine", Value: ListScopeMine, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Meine"}},
+							{Name: "This channel", Value: ListScopeChannel, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Dieser Kanal"}},
+							{Name: "This server", Value: ListScopeGuild, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Dieser Server"}},
+						},
+					},
 				},
 			},
 			{
-				Name:        "delete",
-				Description: "Delete a timer",
+				Name:        "search",
+				Description: "Search your timers by their message",
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
 				Options: []*discordgo.ApplicationCommandOption{
 					{
 						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        "id",
-						Description: "The ID of the timer to delete",
+						Name:        "query",
+						Description: "Text the message of the timer contains",
 						Required:    true,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "tag",
+						Description:  "Only search timers with this tag",
+						Required:     false,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "show_expired",
+						Description: "Whether to include expired timers",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Name:        "delete",
+				Description: "Delete a timer",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "id",
+						Description:  "The ID of the timer to delete",
+						Required:     true,
 						Autocomplete: true,
 					},
 				},
 			},
 			{
 				Name:        "edit",
-				Description: "Edit a timer",
+				Description: "Edit a timer, giving only the ID opens a form",
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
 				Options: []*discordgo.ApplicationCommandOption{
 					{
-						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        "id",
-						Description: "The ID of the timer to edit",
-						Required:    true,
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "id",
+						Description:  "The ID of the timer to edit",
+						Required:     true,
 						Autocomplete: true,
 					},
 					{
@@ -89,10 +203,18 @@ var commands = []*discordgo.ApplicationCommand{
 						Required:    false,
 					},
 					{
-						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        "time",
-						Description: "The new time for the timer",
-						Required:    false,
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "time",
+						Description:  "The new time for the timer",
+						Required:     false,
+						Autocomplete: true,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "tags",
+						Description:  "The new tags of the timer, none removes all tags",
+						Required:     false,
+						Autocomplete: true,
 					},
 				},
 			},
@@ -100,24 +222,630 @@ var commands = []*discordgo.ApplicationCommand{
 				Name:        "snooze",
 				Description: "Snooze a timer",
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "id",
+						Description:  "The ID of the timer to snooze",
+						Required:     true,
+						Autocomplete: true,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "time",
+						Description:  "The amount of time to snooze the timer for",
+						Required:     true,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "relative_to",
+						Description: "Whether a duration counts from now or from the original due time",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Now", Value: "now", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Jetzt"}},
+							{Name: "Original due time", Value: "due", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Ursprüngliche Fälligkeit"}},
+						},
+					},
+				},
+			},
+			{
+				Name:        "clear-expired",
+				Description: "Delete all of your timers that were already shown",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        "restore",
+				Description: "Restore a deleted timer",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "id",
+						Description:  "The ID of the deleted timer",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        "history",
+				Description: "Show everything that happened to a timer",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "id",
+						Description:  "The ID of the timer, also works for deleted timers",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        "template",
+				Description: "Manage reusable timer templates",
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "save",
+						Description: "Save a template, replacing the one with the same name",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "name",
+								Description: "The name of the template, e.g. standup",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "message",
+								Description: "The message to display when the timer is up",
+								Required:    true,
+							},
+							{
+								Type:         discordgo.ApplicationCommandOptionString,
+								Name:         "time",
+								Description:  "The time, read again on every use, e.g. in 1h or monday 09:00",
+								Required:     true,
+								Autocomplete: true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionChannel,
+								Name:        "channel",
+								Description: "Where the timers are sent, by default the channel the template is used in",
+								Required:    false,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "then",
+								Description: "Follow-up timers started one after another, e.g. 15m: verify; 1h: announce",
+								Required:    false,
+							},
+							{
+								Type:         discordgo.ApplicationCommandOptionString,
+								Name:         "tags",
+								Description:  "Tags to organize the timers, e.g. work, billing",
+								Required:     false,
+								Autocomplete: true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionBoolean,
+								Name:        "important",
+								Description: "Ping again until someone acknowledges the timer",
+								Required:    false,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionBoolean,
+								Name:        "guild",
+								Description: "Share the template with everyone in this server, needs Manage Server",
+								Required:    false,
+							},
+						},
+					},
+					{
+						Name:        "use",
+						Description: "Create a timer from a template",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:         discordgo.ApplicationCommandOptionString,
+								Name:         "name",
+								Description:  "The name of the template",
+								Required:     true,
+								Autocomplete: true,
+							},
+							{
+								Type:         discordgo.ApplicationCommandOptionString,
+								Name:         "time",
+								Description:  "Overrides the time of the template",
+								Required:     false,
+								Autocomplete: true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "message",
+								Description: "Overrides the message of the template",
+								Required:    false,
+							},
+						},
+					},
+					{
+						Name:        "list",
+						Description: "List your templates and the ones of this server",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+					},
+					{
+						Name:        "delete",
+						Description: "Delete a template",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:         discordgo.ApplicationCommandOptionString,
+								Name:         "name",
+								Description:  "The name of the template",
+								Required:     true,
+								Autocomplete: true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionBoolean,
+								Name:        "guild",
+								Description: "Delete the template of this server, needs Manage Server",
+								Required:    false,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:        "bulk-delete",
+				Description: "Delete all of your timers matching a search or tag",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
 				Options: []*discordgo.ApplicationCommandOption{
 					{
 						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        "id",
-						Description: "The ID of the timer to snooze",
+						Name:        "query",
+						Description: "Only timers whose message contains this text",
+						Required:    false,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "tag",
+						Description:  "Only timers with this tag",
+						Required:     false,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "expired",
+						Description: "Only timers that were already shown",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Name:        "bulk-snooze",
+				Description: "Snooze all of your timers due today",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "by",
+						Description: "How long to snooze the timers, e.g. 1h or 30m",
 						Required:    true,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "tag",
+						Description:  "Only timers with this tag",
+						Required:     false,
 						Autocomplete: true,
 					},
+				},
+			},
+			{
+				Name:        "bulk-shift",
+				Description: "Move all of your timers in a channel",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
 					{
 						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        "time",
-						Description: "The amount of time to snooze the timer for",
+						Name:        "by",
+						Description: "How far to move the timers, e.g. 2h, or -1d to move them earlier",
 						Required:    true,
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionChannel,
+						Name:        "channel",
+						Description: "The channel of the timers, by default this one",
+						Required:    false,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "tag",
+						Description:  "Only timers with this tag",
+						Required:     false,
+						Autocomplete: true,
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:        "stopwatch",
+		Description: "Measure how long something takes",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "start",
+				Description: "Start a new stopwatch",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "What is being measured",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Name:        "lap",
+				Description: "Record a lap",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "id",
+						Description:  "The ID of the stopwatch",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        "pause",
+				Description: "Pause a stopwatch",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "id",
+						Description:  "The ID of the stopwatch",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        "resume",
+				Description: "Resume a paused stopwatch",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "id",
+						Description:  "The ID of the stopwatch",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        "stop",
+				Description: "Stop a stopwatch for good",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "id",
+						Description:  "The ID of the stopwatch",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        "list",
+				Description: "List your stopwatches",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "show_stopped",
+						Description: "Whether to show stopped stopwatches",
+						Required:    false,
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:        "pomodoro",
+		Description: "Alternate between focused work and breaks",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "start",
+				Description: "Start a pomodoro session",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "work",
+						Description: "Minutes of work per cycle, 25 by default",
+						Required:    false,
+						MinValue:    &pomodoroMinLength,
+						MaxValue:    240,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "break",
+						Description: "Minutes of the breaks between cycles, 5 by default",
+						Required:    false,
+						MinValue:    &pomodoroMinBreak,
+						MaxValue:    120,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "long_break",
+						Description: "Minutes of the long break at the end, 15 by default",
+						Required:    false,
+						MinValue:    &pomodoroMinBreak,
+						MaxValue:    240,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "cycles",
+						Description: "Number of work cycles, 4 by default",
+						Required:    false,
+						MinValue:    &pomodoroMinLength,
+						MaxValue:    12,
+					},
+				},
+			},
+			{
+				Name:        "stats",
+				Description: "Show your completed pomodoro sessions",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+		},
+	},
+	{
+		Name:        "settings",
+		Description: "Manage settings",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "user",
+				Description: "Show or change your personal settings",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "language",
+						Description: "The language the bot responds in",
+						Required:    false,
+						Choices:     languageChoices,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "date_order",
+						Description: "How numeric dates like 03/04 are read",
+						Required:    false,
+						Choices:     dateOrderChoices,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "date_languages",
+						Description: "Languages to understand times in, e.g. de,en or auto",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "default_time",
+						Description: "Time used when only a date is given, e.g. 09:00, now or auto",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "named_times",
+						Description: "Your own names for times of day, e.g. lunch=12:30, standup=09:15",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "timezone",
+						Description: "Your timezone for quiet hours, e.g. Europe/Berlin or auto",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "quiet_hours",
+						Description: "When timers should not ping you, e.g. 22:00-07:00 or none",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "quiet_mode",
+						Description: "What happens to timers due during quiet hours or do-not-disturb",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Deliver afterwards", Value: QuietModeDefer, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Danach zustellen"}},
+							{Name: "Deliver silently", Value: QuietModeSilent, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Still zustellen"}},
+							{Name: "Automatic", Value: "auto", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Automatisch"}},
+						},
+					},
+				},
+			},
+			{
+				Name:        "guild",
+				Description: "Show or change the settings of this server",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "language",
+						Description: "The default language of responses in this server",
+						Required:    false,
+						Choices:     languageChoices,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "date_order",
+						Description: "How numeric dates like 03/04 are read in this server",
+						Required:    false,
+						Choices:     dateOrderChoices,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "date_languages",
+						Description: "Languages to understand times in for this server, e.g. de,en or auto",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "default_time",
+						Description: "Time used when only a date is given, e.g. 09:00, now or auto",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "named_times",
+						Description: "Your own names for times of day, e.g. lunch=12:30, standup=09:15",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "weekend",
+						Description: "Days without work in this server, e.g. sat,sun, none or auto",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "holidays",
+						Description: "Holiday calendars and extra days off, e.g. de, 2030-12-24, 12-31 or none",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "max_snoozes",
+						Description: "How often timers can be snoozed in this server, e.g. 3, or none",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "snooze_limit_action",
+						Description: "What happens to a timer that reached the snooze limit",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Escalate", Value: SnoozeLimitEscalate, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Eskalieren"}},
+							{Name: "Expire", Value: SnoozeLimitExpire, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Ablaufen lassen"}},
+							{Name: "Automatic", Value: "auto", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Automatisch"}},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "quiet_hours",
+						Description: "When timers of this server should not ping, e.g. 22:00-07:00 or none",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "quiet_mode",
+						Description: "What happens to timers of this server due during quiet hours",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Deliver afterwards", Value: QuietModeDefer, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Danach zustellen"}},
+							{Name: "Deliver silently", Value: QuietModeSilent, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Still zustellen"}},
+							{Name: "Automatic", Value: "auto", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Automatisch"}},
+						},
+					},
 				},
 			},
 		},
 	},
+	{
+		Name:        "dnd",
+		Description: "Hold back timer notifications for a while",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "start",
+				Description: "Don't get pinged by timers until the given time",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "until",
+						Description:  "When timers may ping you again, e.g. monday 08:00",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        "stop",
+				Description: "End the do-not-disturb early",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+		},
+	},
+	{
+		Name:        "board",
+		Description: "Keep a message with the upcoming timers in this channel",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "set",
+				Description: "Post the board to this channel, replacing its previous board",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "scope",
+						Description: "Which timers the board shows, the ones of this channel by default",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "This channel", Value: ListScopeChannel, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Dieser Kanal"}},
+							{Name: "This server", Value: ListScopeGuild, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Dieser Server"}},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "count",
+						Description: "How many timers the board shows, 10 by default",
+						Required:    false,
+						MinValue:    &minBoardSize,
+						MaxValue:    maxListedTimers,
+					},
+				},
+			},
+			{
+				Name:        "remove",
+				Description: "Remove the board of this channel",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+		},
+	},
+}
+
+// Discord takes the minimum of integer options as a pointer
+var (
+	pomodoroMinLength = 1.0
+	pomodoroMinBreak  = 0.0
+	minSnoozeLimit    = 1.0
+	minRepingInterval = 1.0
+	minBoardSize      = 1.0
+)
+
+var languageChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "Automatic", Value: "auto", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Automatisch"}},
+	{Name: "English", Value: LanguageEnglish, NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Englisch"}},
+	{Name: "Deutsch", Value: LanguageGerman},
+}
+
+var dateOrderChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "Automatic", Value: "auto", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Automatisch"}},
+	{Name: "Day/Month/Year", Value: "DMY", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Tag/Monat/Jahr"}},
+	{Name: "Month/Day/Year", Value: "MDY", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Monat/Tag/Jahr"}},
+	{Name: "Year/Month/Day", Value: "YMD", NameLocalizations: map[discordgo.Locale]string{discordgo.German: "Jahr/Monat/Tag"}},
 }
 
 func interactionCreate(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
@@ -127,43 +855,88 @@ func interactionCreate(session *discordgo.Session, interaction *discordgo.Intera
 			handleUntil(session, interaction)
 		case "timer":
 			handleTimer(session, interaction)
+		case "stopwatch":
+			handleStopwatch(session, interaction)
+		case "pomodoro":
+			handlePomodoro(session, interaction)
+		case "settings":
+			handleSettings(session, interaction)
+		case "dnd":
+			handleDND(session, interaction)
+		case "board":
+			handleBoard(session, interaction)
+		}
+		return
+	}
+
+	if interaction.Type == discordgo.InteractionMessageComponent {
+		action, argument, _ := strings.Cut(interaction.MessageComponentData().CustomID, ":")
+		switch action {
+		case "time_choice":
+			handleTimeChoice(session, interaction, argument)
+		case "pomodoro":
+			handlePomodoroButton(session, interaction, argument)
+		case "timer_restore":
+			completeTimerRestore(session, interaction, argument, discordgo.InteractionResponseUpdateMessage)
+		case "timer_ack":
+			handleTimerAcknowledge(session, interaction, argument)
+		case "timer_bulk":
+			handleTimerBulkButton(session, interaction, argument)
+		}
+		return
+	}
+
+	if interaction.Type == discordgo.InteractionModalSubmit {
+		action, argument, _ := strings.Cut(interaction.ModalSubmitData().CustomID, ":")
+		switch action {
+		case "timer_modal":
+			handleTimerModal(session, interaction, argument)
 		}
 		return
 	}
 
 	if interaction.Type == discordgo.InteractionApplicationCommandAutocomplete {
 		switch interaction.ApplicationCommandData().Name {
+		case "until":
+			handleTimeAutocomplete(session, interaction, interaction.ApplicationCommandData().Options[0].StringValue(), nil)
 		case "timer":
 			handleTimerAutocomplete(session, interaction)
+		case "stopwatch":
+			handleStopwatchAutocomplete(session, interaction)
+		case "dnd":
+			handleTimeAutocomplete(session, interaction, interaction.ApplicationCommandData().Options[0].Options[0].StringValue(), nil)
 		}
 	}
 }
 
 func handleUntil(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
 	dateStr := interaction.ApplicationCommandData().Options[0].StringValue()
-	date, err := parseTime(dateStr)
+	date, err := parseTimeWithOptions(dateStr, getParseOptions(interaction))
 	if err != nil {
 		err = session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
-				Content: "Invalid date format",
+				Content: describeParseError(err, language),
 			},
 		})
 
 		if err != nil {
-			fmt.Println("Error sending message in date parsing failed in handleUntil():", err)
+			slog.Error("Sending message in date parsing failed in handleUntil()", "error", err)
 		}
 
 		return
 	}
 
+	businessDays := getBusinessCalendar(interaction.GuildID).CountBusinessDays(time.Now(), date)
+
 	err = session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: []*discordgo.MessageEmbed{
 				{
-					Title:       "Time until",
-					Description: fmt.Sprintf("Time until <t:%d:F>: %s", date.Unix(), humanize.Time(date)),
+					Title:       tr(language, "until.title"),
+					Description: tr(language, "until.description", date.Unix(), humanizeTime(date, language)) + "\n" + tr(language, "until.business_days", businessDays),
 					Color:       0x00ff00,
 				},
 			},
@@ -171,6 +944,6 @@ func handleUntil(session *discordgo.Session, interaction *discordgo.InteractionC
 	})
 
 	if err != nil {
-		fmt.Println("Error sending message in handleUntil():", err)
+		slog.Error("Sending message in handleUntil()", "error", err)
 	}
 }
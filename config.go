@@ -0,0 +1,385 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Token         string          `yaml:"token" toml:"token"`
+	ApplicationID string          `yaml:"application_id" toml:"application_id"`
+	Timezone      string          `yaml:"timezone" toml:"timezone"`
+	Database      DatabaseConfig  `yaml:"database" toml:"database"`
+	Scheduler     SchedulerConfig `yaml:"scheduler" toml:"scheduler"`
+	HTTP          HTTPConfig      `yaml:"http" toml:"http"`
+	Limits        LimitsConfig    `yaml:"limits" toml:"limits"`
+	Retention     RetentionConfig `yaml:"retention" toml:"retention"`
+	IDs           IDConfig        `yaml:"ids" toml:"ids"`
+	Log           LogConfig       `yaml:"log" toml:"log"`
+}
+
+type DatabaseConfig struct {
+	Path string `yaml:"path" toml:"path"`
+}
+
+type SchedulerConfig struct {
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+}
+
+type HTTPConfig struct {
+	// Listen is the address of the health check listener, empty disables it
+	Listen string `yaml:"listen" toml:"listen"`
+}
+
+type LimitsConfig struct {
+	// MaxTimersPerUser caps the number of active timers per user, 0 means unlimited
+	MaxTimersPerUser int `yaml:"max_timers_per_user" toml:"max_timers_per_user"`
+	MaxMessageLength int `yaml:"max_message_length" toml:"max_message_length"`
+}
+
+type RetentionConfig struct {
+	// Interval is how often the retention policies are applied
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+	// DeletedTimers is how long deleted timers can be restored before they are purged
+	DeletedTimers time.Duration `yaml:"deleted_timers" toml:"deleted_timers"`
+	// ShownTimers is how long timers are kept after they were shown, 0 keeps them forever
+	ShownTimers time.Duration `yaml:"shown_timers" toml:"shown_timers"`
+	// KeepPerUser caps the number of shown timers kept per user, 0 means unlimited
+	KeepPerUser int `yaml:"keep_per_user" toml:"keep_per_user"`
+	// Archive moves removed shown timers with their snoozes and tags to the archive tables instead of deleting them
+	Archive bool `yaml:"archive" toml:"archive"`
+}
+
+type IDConfig struct {
+	// Scheme is how new timer IDs look: code, words or number. Numbers count
+	// up across all users, so a user's timers don't get consecutive numbers.
+	Scheme string `yaml:"scheme" toml:"scheme"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" toml:"level"`
+	Format string `yaml:"format" toml:"format"`
+}
+
+// config holds the effective configuration of the running process
+var config = defaultConfig()
+
+func defaultConfig() *Config {
+	return &Config{
+		Database: DatabaseConfig{
+			Path: "/app/data/timerbot.db",
+		},
+		Scheduler: SchedulerConfig{
+			Interval: 60 * time.Second,
+		},
+		Limits: LimitsConfig{
+			MaxTimersPerUser: 0,
+			MaxMessageLength: 1000,
+		},
+		Retention: RetentionConfig{
+			Interval:      time.Hour,
+			DeletedTimers: 7 * 24 * time.Hour,
+		},
+		IDs: IDConfig{
+			Scheme: IDSchemeCode,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+	}
+}
+
+// configKey describes a single setting that can be overridden by an
+// environment variable and a command-line flag
+type configKey struct {
+	flag  string
+	env   string
+	usage string
+	set   func(cfg *Config, value string) error
+}
+
+var configKeys = []configKey{
+	{"token", "TOKEN", "Discord bot token", func(cfg *Config, v string) error {
+		cfg.Token = v
+		return nil
+	}},
+	{"application-id", "APPLICATION_ID", "Discord application ID", func(cfg *Config, v string) error {
+		cfg.ApplicationID = v
+		return nil
+	}},
+	{"timezone", "DEFAULT_TIMEZONE", "Default timezone, e.g. Europe/Berlin", func(cfg *Config, v string) error {
+		cfg.Timezone = v
+		return nil
+	}},
+	{"database", "DATABASE_URL", "Path to the SQLite database file", func(cfg *Config, v string) error {
+		cfg.Database.Path = v
+		return nil
+	}},
+	{"scheduler-interval", "SCHEDULER_INTERVAL", "How often due timers are checked, e.g. 30s", func(cfg *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		cfg.Scheduler.Interval = d
+		return nil
+	}},
+	{"http-listen", "HTTP_LISTEN", "Address of the health check listener, e.g. :8080", func(cfg *Config, v string) error {
+		cfg.HTTP.Listen = v
+		return nil
+	}},
+	{"max-timers-per-user", "MAX_TIMERS_PER_USER", "Maximum number of active timers per user, 0 for unlimited", func(cfg *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		cfg.Limits.MaxTimersPerUser = n
+		return nil
+	}},
+	{"max-message-length", "MAX_MESSAGE_LENGTH", "Maximum length of a timer message", func(cfg *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		cfg.Limits.MaxMessageLength = n
+		return nil
+	}},
+	{"retention-interval", "RETENTION_INTERVAL", "How often the retention policies are applied, e.g. 1h", func(cfg *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		cfg.Retention.Interval = d
+		return nil
+	}},
+	{"retention-deleted-timers", "RETENTION_DELETED_TIMERS", "How long deleted timers can be restored, e.g. 168h", func(cfg *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		cfg.Retention.DeletedTimers = d
+		return nil
+	}},
+	{"retention-shown-timers", "RETENTION_SHOWN_TIMERS", "How long shown timers are kept, e.g. 2160h, 0 for forever", func(cfg *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		cfg.Retention.ShownTimers = d
+		return nil
+	}},
+	{"retention-keep-per-user", "RETENTION_KEEP_PER_USER", "Maximum number of shown timers kept per user, 0 for unlimited", func(cfg *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		cfg.Retention.KeepPerUser = n
+		return nil
+	}},
+	{"retention-archive", "RETENTION_ARCHIVE", "Archive shown timers instead of deleting them: true or false", func(cfg *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		cfg.Retention.Archive = b
+		return nil
+	}},
+	{"id-scheme", "ID_SCHEME", "How new timer IDs look: code, words or number (counted across all users)", func(cfg *Config, v string) error {
+		cfg.IDs.Scheme = v
+		return nil
+	}},
+	{"log-level", "LOG_LEVEL", "Log level: debug, info, warn or error", func(cfg *Config, v string) error {
+		cfg.Log.Level = v
+		return nil
+	}},
+	{"log-format", "LOG_FORMAT", "Log format: text or json", func(cfg *Config, v string) error {
+		cfg.Log.Format = v
+		return nil
+	}},
+}
+
+// loadConfig builds the configuration from, in increasing order of
+// precedence, the defaults, the config file, environment variables and
+// command-line flags. It returns the arguments left over after the flags.
+func loadConfig(name string, args []string, lookupEnv func(string) (string, bool)) (*Config, []string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configPath, _ := lookupEnv("CONFIG_FILE")
+	fs.StringVar(&configPath, "config", configPath, "Path to a YAML or TOML config file")
+
+	flagValues := make(map[string]string)
+	for _, key := range configKeys {
+		fs.Func(key.flag, fmt.Sprintf("%s (env %s)", key.usage, key.env), func(v string) error {
+			flagValues[key.flag] = v
+			return nil
+		})
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	cfg := defaultConfig()
+	if configPath != "" {
+		if err := loadConfigFile(cfg, configPath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, key := range configKeys {
+		if v, ok := lookupEnv(key.env); ok && v != "" {
+			if err := key.set(cfg, v); err != nil {
+				return nil, nil, fmt.Errorf("invalid value for %s: %w", key.env, err)
+			}
+		}
+	}
+
+	for _, key := range configKeys {
+		if v, ok := flagValues[key.flag]; ok {
+			if err := key.set(cfg, v); err != nil {
+				return nil, nil, fmt.Errorf("invalid value for --%s: %w", key.flag, err)
+			}
+		}
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+func loadConfigFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file type %q, expected .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// validate reports every problem with the configuration at once so that
+// operators don't have to fix them one restart at a time
+func (cfg *Config) validate() error {
+	var errs []error
+
+	if cfg.Token == "" {
+		errs = append(errs, errors.New("token is required"))
+	}
+	if cfg.ApplicationID == "" {
+		errs = append(errs, errors.New("application_id is required"))
+	}
+	if cfg.Database.Path == "" {
+		errs = append(errs, errors.New("database.path is required"))
+	}
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("timezone %q is invalid: %w", cfg.Timezone, err))
+		}
+	}
+	if cfg.Scheduler.Interval < time.Second {
+		errs = append(errs, fmt.Errorf("scheduler.interval must be at least 1s, got %s", cfg.Scheduler.Interval))
+	}
+	if cfg.HTTP.Listen != "" {
+		if _, _, err := net.SplitHostPort(cfg.HTTP.Listen); err != nil {
+			errs = append(errs, fmt.Errorf("http.listen %q is invalid: %w", cfg.HTTP.Listen, err))
+		}
+	}
+	if cfg.Limits.MaxTimersPerUser < 0 {
+		errs = append(errs, errors.New("limits.max_timers_per_user must not be negative"))
+	}
+	if cfg.Limits.MaxMessageLength < 1 || cfg.Limits.MaxMessageLength > 4096 {
+		errs = append(errs, errors.New("limits.max_message_length must be between 1 and 4096"))
+	}
+	if cfg.Retention.Interval < time.Minute {
+		errs = append(errs, fmt.Errorf("retention.interval must be at least 1m, got %s", cfg.Retention.Interval))
+	}
+	if cfg.Retention.DeletedTimers < time.Minute {
+		errs = append(errs, fmt.Errorf("retention.deleted_timers must be at least 1m, got %s", cfg.Retention.DeletedTimers))
+	}
+	if cfg.Retention.ShownTimers < 0 {
+		errs = append(errs, errors.New("retention.shown_timers must not be negative"))
+	}
+	if cfg.Retention.KeepPerUser < 0 {
+		errs = append(errs, errors.New("retention.keep_per_user must not be negative"))
+	}
+	if !slices.Contains(idSchemes, cfg.IDs.Scheme) {
+		errs = append(errs, fmt.Errorf("ids.scheme must be one of %s, got %q", strings.Join(idSchemes, ", "), cfg.IDs.Scheme))
+	}
+	if _, err := parseLogLevel(cfg.Log.Level); err != nil {
+		errs = append(errs, err)
+	}
+	if cfg.Log.Format != "text" && cfg.Log.Format != "json" {
+		errs = append(errs, fmt.Errorf("log.format must be text or json, got %q", cfg.Log.Format))
+	}
+
+	return errors.Join(errs...)
+}
+
+// apply sets up the process wide state that depends on the configuration
+func (cfg *Config) apply() error {
+	if cfg.Timezone != "" {
+		location, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return err
+		}
+		time.Local = location
+	}
+
+	level, err := parseLogLevel(cfg.Log.Level)
+	if err != nil {
+		return err
+	}
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewTextHandler(os.Stderr, options)
+	if cfg.Log.Format == "json" {
+		handler = slog.NewJSONHandler(os.Stderr, options)
+	}
+	slog.SetDefault(slog.New(handler))
+
+	return nil
+}
+
+func parseLogLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return l, fmt.Errorf("log.level must be debug, info, warn or error, got %q", level)
+	}
+	return l, nil
+}
+
+// print writes the configuration as YAML with secrets redacted
+func (cfg *Config) print(w io.Writer) error {
+	redacted := *cfg
+	if redacted.Token != "" {
+		redacted.Token = "<redacted>"
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&redacted); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
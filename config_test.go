@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envFromMap(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, _, err := loadConfig("test", nil, envFromMap(nil))
+		require.NoError(t, err)
+		assert.Equal(t, defaultConfig(), cfg)
+	})
+
+	t.Run("flags override env which overrides the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte("token: file\napplication_id: file\nscheduler:\n  interval: 10s\nlog:\n  level: warn\n"), 0o600)
+		require.NoError(t, err)
+
+		env := map[string]string{
+			"CONFIG_FILE":    path,
+			"APPLICATION_ID": "env",
+			"LOG_LEVEL":      "error",
+		}
+		cfg, _, err := loadConfig("test", []string{"--log-level", "debug"}, envFromMap(env))
+		require.NoError(t, err)
+
+		assert.Equal(t, "file", cfg.Token)
+		assert.Equal(t, "env", cfg.ApplicationID)
+		assert.Equal(t, 10*time.Second, cfg.Scheduler.Interval)
+		assert.Equal(t, "debug", cfg.Log.Level)
+	})
+
+	t.Run("toml file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		err := os.WriteFile(path, []byte("token = \"file\"\n[limits]\nmax_timers_per_user = 5\n[retention]\ndeleted_timers = \"48h\"\n"), 0o600)
+		require.NoError(t, err)
+
+		cfg, _, err := loadConfig("test", []string{"--config", path}, envFromMap(nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, "file", cfg.Token)
+		assert.Equal(t, 5, cfg.Limits.MaxTimersPerUser)
+		assert.Equal(t, 48*time.Hour, cfg.Retention.DeletedTimers)
+	})
+
+	t.Run("invalid values are rejected", func(t *testing.T) {
+		_, _, err := loadConfig("test", nil, envFromMap(map[string]string{"SCHEDULER_INTERVAL": "often"}))
+		assert.Error(t, err)
+
+		_, _, err = loadConfig("test", []string{"--max-timers-per-user", "many"}, envFromMap(nil))
+		assert.Error(t, err)
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Token = "token"
+	cfg.ApplicationID = "id"
+	require.NoError(t, cfg.validate())
+
+	cfg.Timezone = "Mars/Olympus_Mons"
+	cfg.Scheduler.Interval = 0
+	cfg.Log.Format = "xml"
+	cfg.Retention.DeletedTimers = 0
+	cfg.Retention.KeepPerUser = -1
+	err := cfg.validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timezone")
+	assert.Contains(t, err.Error(), "scheduler.interval")
+	assert.Contains(t, err.Error(), "log.format")
+	assert.Contains(t, err.Error(), "retention.deleted_timers")
+	assert.Contains(t, err.Error(), "retention.keep_per_user")
+}
@@ -3,8 +3,9 @@ package main
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"math/rand/v2"
-	"os"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -12,13 +13,9 @@ import (
 
 var db *sql.DB
 
-func initDB() error {
+func initDB(path string) error {
 	var err error
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		dbURL = "/app/data/timerbot.db"
-	}
-	db, err = sql.Open("sqlite3", dbURL)
+	db, err = sql.Open("sqlite3", path)
 	if err != nil {
 		return err
 	}
@@ -37,48 +34,287 @@ func initDB() error {
 			shown BOOLEAN DEFAULT false
 		)
 	`)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return migrateDB()
+}
+
+// migrations are applied in order on top of the initial schema, the index
+// of the last applied migration is tracked in PRAGMA user_version. Only
+// ever append to this list.
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS settings (
+		scope TEXT NOT NULL,
+		scopeId TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY (scope, scopeId, key)
+	)`,
+	`ALTER TABLE timers ADD COLUMN language TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE timers ADD COLUMN followUps TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE timers ADD COLUMN chainId TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE timers ADD COLUMN chainPosition INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE timers ADD COLUMN chainLength INTEGER NOT NULL DEFAULT 1`,
+	`CREATE TABLE IF NOT EXISTS stopwatches (
+		internalId INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT UNIQUE,
+		name TEXT NOT NULL DEFAULT '',
+		user TEXT,
+		channel TEXT,
+		started DATETIME,
+		pausedAt DATETIME,
+		pausedDuration INTEGER NOT NULL DEFAULT 0,
+		stopped DATETIME,
+		laps TEXT NOT NULL DEFAULT '',
+		language TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS pomodoros (
+		internalId INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT UNIQUE,
+		user TEXT,
+		channel TEXT,
+		workLength INTEGER NOT NULL,
+		breakLength INTEGER NOT NULL,
+		longBreakLength INTEGER NOT NULL,
+		cycles INTEGER NOT NULL,
+		phase INTEGER NOT NULL DEFAULT 0,
+		state TEXT NOT NULL,
+		timerId TEXT NOT NULL DEFAULT '',
+		phaseDue DATETIME,
+		remaining INTEGER NOT NULL DEFAULT 0,
+		completedCycles INTEGER NOT NULL DEFAULT 0,
+		started DATETIME,
+		finished DATETIME,
+		language TEXT NOT NULL DEFAULT ''
+	)`,
+	`ALTER TABLE timers ADD COLUMN pomodoroId TEXT NOT NULL DEFAULT ''`,
+	`CREATE TABLE IF NOT EXISTS snoozes (
+		internalId INTEGER PRIMARY KEY AUTOINCREMENT,
+		timerId TEXT NOT NULL,
+		fromDue DATETIME NOT NULL,
+		toDue DATETIME NOT NULL,
+		snoozedAt DATETIME NOT NULL,
+		user TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS snoozesByTimer ON snoozes (timerId)`,
+	`CREATE INDEX IF NOT EXISTS snoozesByUser ON snoozes (user, snoozedAt)`,
+	`ALTER TABLE timers ADD COLUMN maxSnoozes INTEGER NOT NULL DEFAULT 0`,
+	`CREATE TABLE IF NOT EXISTS timer_events (
+		internalId INTEGER PRIMARY KEY AUTOINCREMENT,
+		timerId TEXT NOT NULL,
+		owner TEXT NOT NULL,
+		event TEXT NOT NULL,
+		actor TEXT NOT NULL DEFAULT '',
+		time DATETIME NOT NULL,
+		details TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE INDEX IF NOT EXISTS timerEventsByTimer ON timer_events (timerId, owner)`,
+	`ALTER TABLE timers ADD COLUMN deletedAt DATETIME`,
+	`CREATE TABLE IF NOT EXISTS timers_archive (
+		internalId INTEGER PRIMARY KEY,
+		id TEXT,
+		message TEXT,
+		user TEXT,
+		channel TEXT,
+		creation DATETIME,
+		due DATETIME,
+		snoozedDue DATETIME,
+		snoozeCount INTEGER,
+		shown BOOLEAN,
+		language TEXT,
+		followUps TEXT,
+		chainId TEXT,
+		chainPosition INTEGER,
+		chainLength INTEGER,
+		pomodoroId TEXT,
+		maxSnoozes INTEGER,
+		deletedAt DATETIME,
+		archivedAt DATETIME NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS timersByShownUser ON timers (shown, user, snoozedDue)`,
+	`CREATE TABLE IF NOT EXISTS sequences (
+		name TEXT PRIMARY KEY,
+		value INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS timer_tags (
+		timerId TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (timerId, tag)
+	)`,
+	`CREATE INDEX IF NOT EXISTS timerTagsByTag ON timer_tags (tag)`,
+	`ALTER TABLE timers ADD COLUMN acknowledgedAt DATETIME`,
+	`ALTER TABLE timers ADD COLUMN repingInterval INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE timers ADD COLUMN lastPing DATETIME`,
+	`ALTER TABLE timers ADD COLUMN escalateTo TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE timers ADD COLUMN escalateAfter INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE timers ADD COLUMN escalatedAt DATETIME`,
+	`ALTER TABLE timers_archive ADD COLUMN acknowledgedAt DATETIME`,
+	`ALTER TABLE timers_archive ADD COLUMN repingInterval INTEGER`,
+	`ALTER TABLE timers_archive ADD COLUMN lastPing DATETIME`,
+	`ALTER TABLE timers_archive ADD COLUMN escalateTo TEXT`,
+	`ALTER TABLE timers_archive ADD COLUMN escalateAfter INTEGER`,
+	`ALTER TABLE timers_archive ADD COLUMN escalatedAt DATETIME`,
+	`ALTER TABLE timers ADD COLUMN deferredFrom DATETIME`,
+	`ALTER TABLE timers_archive ADD COLUMN deferredFrom DATETIME`,
+	`CREATE TABLE IF NOT EXISTS timer_templates (
+		scope TEXT NOT NULL,
+		scopeId TEXT NOT NULL,
+		name TEXT NOT NULL,
+		message TEXT NOT NULL,
+		time TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		followUps TEXT NOT NULL DEFAULT '',
+		tags TEXT NOT NULL DEFAULT '',
+		important BOOLEAN NOT NULL DEFAULT false,
+		PRIMARY KEY (scope, scopeId, name)
+	)`,
+	`ALTER TABLE timers ADD COLUMN guild TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE timers ADD COLUMN private BOOLEAN NOT NULL DEFAULT false`,
+	`ALTER TABLE timers_archive ADD COLUMN guild TEXT`,
+	`ALTER TABLE timers_archive ADD COLUMN private BOOLEAN`,
+	`CREATE INDEX IF NOT EXISTS timersByChannel ON timers (channel, snoozedDue)`,
+	`CREATE TABLE IF NOT EXISTS timer_boards (
+		channel TEXT PRIMARY KEY,
+		guild TEXT NOT NULL,
+		messageId TEXT NOT NULL,
+		scope TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		language TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS snoozes_archive (
+		internalId INTEGER PRIMARY KEY,
+		timerId TEXT NOT NULL,
+		fromDue DATETIME NOT NULL,
+		toDue DATETIME NOT NULL,
+		snoozedAt DATETIME NOT NULL,
+		user TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS timer_tags_archive (
+		timerId TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (timerId, tag)
+	)`,
 }
 
-func createTimer(id string, message string, userId string, channelId string, due time.Time) (*Timer, error) {
-	created := time.Now()
-	_, err := db.Exec("INSERT INTO timers (id, message, user, channel, creation, due, snoozedDue) VALUES (?, ?, ?, ?, ?, ?, ?)", id, message, userId, channelId, created, due, due)
+func migrateDB() error {
+	var version int
+	err := db.QueryRow("PRAGMA user_version").Scan(&version)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(migrations[i])
+		if err == nil {
+			_, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1))
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("applying migration %d: %w", i+1, err)
+		}
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
 	}
 
-	return &Timer{
-		ID:          id,
-		Message:     message,
-		User:        userId,
-		Channel:     channelId,
-		Created:     created,
-		Due:         due,
-		SnoozedDue:  due,
-		SnoozeCount: 0,
-		Shown:       false,
-	}, nil
+	return nil
 }
 
-func getTimerByID(id string) (*Timer, error) {
-	row := db.QueryRow("SELECT internalId, id, message, user, channel, creation, due, snoozedDue, snoozeCount, shown FROM timers WHERE id = ?", id)
-	timer := &Timer{}
+func createTimer(id string, message string, userId string, channelId string, due time.Time, language string, options TimerOptions) (*Timer, error) {
+	timer := &Timer{
+		ID:             id,
+		Message:        message,
+		User:           userId,
+		Channel:        channelId,
+		Created:        time.Now(),
+		Due:            due,
+		SnoozedDue:     due,
+		SnoozeCount:    0,
+		Shown:          false,
+		Language:       language,
+		FollowUps:      options.FollowUps,
+		ChainID:        id,
+		ChainPosition:  1,
+		ChainLength:    len(options.FollowUps) + 1,
+		MaxSnoozes:     options.MaxSnoozes,
+		Tags:           options.Tags,
+		RepingInterval: options.RepingInterval,
+		EscalateTo:     options.EscalateTo,
+		EscalateAfter:  options.EscalateAfter,
+		Guild:          options.Guild,
+		Private:        options.Private,
+	}
 
-	err := row.Scan(&timer.InternalID, &timer.ID, &timer.Message, &timer.User, &timer.Channel, &timer.Created, &timer.Due, &timer.SnoozedDue, &timer.SnoozeCount, &timer.Shown)
+	err := insertTimer(timer)
+	if err != nil {
+		return nil, err
+	}
+	err = setTimerTags(timer.ID, timer.Tags)
 	if err != nil {
 		return nil, err
 	}
+	return timer, nil
+}
 
+// insertTimer stores a new timer, also used for the follow-ups of a chain
+func insertTimer(timer *Timer) error {
+	followUps, err := encodeFollowUps(timer.FollowUps)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(
+		"INSERT INTO timers (id, message, user, channel, creation, due, snoozedDue, language, followUps, chainId, chainPosition, chainLength, pomodoroId, maxSnoozes, repingInterval, escalateTo, escalateAfter, guild, private) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		timer.ID, timer.Message, timer.User, timer.Channel, timer.Created, timer.Due, timer.SnoozedDue, timer.Language, followUps, timer.ChainID, timer.ChainPosition, timer.ChainLength, timer.PomodoroID, timer.MaxSnoozes, timer.RepingInterval, timer.EscalateTo, timer.EscalateAfter, timer.Guild, timer.Private,
+	)
+	return err
+}
+
+// timerColumns lists the columns of the timers table in the order scanTimer
+// expects them. New columns also have to be added to timers_archive.
+const timerColumns = "internalId, id, message, user, channel, creation, due, snoozedDue, snoozeCount, shown, language, followUps, chainId, chainPosition, chainLength, pomodoroId, maxSnoozes, deletedAt, acknowledgedAt, repingInterval, lastPing, escalateTo, escalateAfter, escalatedAt, deferredFrom, guild, private"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTimer(row rowScanner) (*Timer, error) {
+	timer := &Timer{}
+	var followUps string
+	var deletedAt, acknowledgedAt, lastPing, escalatedAt, deferredFrom sql.NullTime
+	err := row.Scan(&timer.InternalID, &timer.ID, &timer.Message, &timer.User, &timer.Channel, &timer.Created, &timer.Due, &timer.SnoozedDue, &timer.SnoozeCount, &timer.Shown, &timer.Language, &followUps, &timer.ChainID, &timer.ChainPosition, &timer.ChainLength, &timer.PomodoroID, &timer.MaxSnoozes, &deletedAt,
+		&acknowledgedAt, &timer.RepingInterval, &lastPing, &timer.EscalateTo, &timer.EscalateAfter, &escalatedAt, &deferredFrom, &timer.Guild, &timer.Private)
+	if err != nil {
+		return nil, err
+	}
+	timer.DeletedAt = nullTimePointer(deletedAt)
+	timer.AcknowledgedAt = nullTimePointer(acknowledgedAt)
+	timer.LastPing = nullTimePointer(lastPing)
+	timer.EscalatedAt = nullTimePointer(escalatedAt)
+	timer.DeferredFrom = nullTimePointer(deferredFrom)
+	timer.FollowUps, err = decodeFollowUps(followUps)
+	if err != nil {
+		return nil, fmt.Errorf("decoding follow-ups of timer %s: %w", timer.ID, err)
+	}
 	return timer, nil
 }
 
-func getAllTimersForUser(userID string, onlyActive bool) ([]*Timer, error) {
-	query := "SELECT internalId, id, message, user, channel, creation, due, snoozedDue, snoozeCount, shown FROM timers WHERE user = ?"
-	if onlyActive {
-		query += " AND shown = false"
+func nullTimePointer(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
 	}
-	rows, err := db.Query(query, userID)
+	return &t.Time
+}
+
+func queryTimers(query string, args ...any) ([]*Timer, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -88,14 +324,127 @@ func getAllTimersForUser(userID string, onlyActive bool) ([]*Timer, error) {
 
 	var timers []*Timer
 	for rows.Next() {
-		timer := &Timer{}
-		err := rows.Scan(&timer.InternalID, &timer.ID, &timer.Message, &timer.User, &timer.Channel, &timer.Created, &timer.Due, &timer.SnoozedDue, &timer.SnoozeCount, &timer.Shown)
+		timer, err := scanTimer(rows)
 		if err != nil {
 			return nil, err
 		}
 		timers = append(timers, timer)
 	}
-	return timers, err
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return timers, attachTags(timers)
+}
+
+// queryTimer returns sql.ErrNoRows if there is no such timer
+func queryTimer(query string, args ...any) (*Timer, error) {
+	timer, err := scanTimer(db.QueryRow(query, args...))
+	if err != nil {
+		return nil, err
+	}
+	return timer, attachTags([]*Timer{timer})
+}
+
+func getTimerByID(id string) (*Timer, error) {
+	return queryTimer("SELECT "+timerColumns+" FROM timers WHERE id = ? AND deletedAt IS NULL", id)
+}
+
+// restorablePomodoroTimer matches the timers that are not part of a pomodoro
+// and the timer of a running phase, a session whose timer was deleted waits
+// for it to be restored. Other phase timers were replaced by their session.
+const restorablePomodoroTimer = `(pomodoroId = '' OR EXISTS (
+	SELECT 1 FROM pomodoros WHERE pomodoros.id = timers.pomodoroId AND pomodoros.timerId = timers.id AND pomodoros.state = '` + PomodoroStateRunning + `'
+))`
+
+// getDeletedTimerByID returns a soft-deleted timer that was not purged yet
+func getDeletedTimerByID(id string) (*Timer, error) {
+	return queryTimer("SELECT "+timerColumns+" FROM timers WHERE id = ? AND deletedAt IS NOT NULL AND "+restorablePomodoroTimer, id)
+}
+
+func getDeletedTimersForUser(userID string) ([]*Timer, error) {
+	return queryTimers("SELECT "+timerColumns+" FROM timers WHERE user = ? AND deletedAt IS NOT NULL AND "+restorablePomodoroTimer+" ORDER BY deletedAt DESC", userID)
+}
+
+func getAllTimersForUser(userID string, onlyActive bool) ([]*Timer, error) {
+	query := "SELECT " + timerColumns + " FROM timers WHERE user = ? AND deletedAt IS NULL"
+	if onlyActive {
+		query += " AND shown = false"
+	}
+	return queryTimers(query, userID)
+}
+
+// TimerFilter narrows down the timers returned by listTimers, zero values match everything
+type TimerFilter struct {
+	User       string
+	Channel    string
+	DueBefore  time.Time
+	OnlyActive bool
+	// OnlyExpired matches the timers that were already shown
+	OnlyExpired bool
+	Tag         string
+	// Query matches timers whose message contains it, ignoring case
+	Query string
+	// Guild matches the timers created in the guild. Timers from before the
+	// guild was stored are found by GuildChannels, the channels of the guild.
+	Guild         string
+	GuildChannels []string
+	// HidePrivate leaves out private timers except the ones of Viewer
+	HidePrivate bool
+	Viewer      string
+}
+
+func listTimers(filter TimerFilter) ([]*Timer, error) {
+	query := "SELECT " + timerColumns + " FROM timers WHERE deletedAt IS NULL"
+	var args []any
+	if filter.User != "" {
+		query += " AND user = ?"
+		args = append(args, filter.User)
+	}
+	if filter.Channel != "" {
+		query += " AND channel = ?"
+		args = append(args, filter.Channel)
+	}
+	if !filter.DueBefore.IsZero() {
+		query += " AND snoozedDue < ?"
+		args = append(args, filter.DueBefore)
+	}
+	if filter.OnlyActive {
+		query += " AND shown = false"
+	}
+	if filter.OnlyExpired {
+		query += " AND shown = true"
+	}
+	if filter.Tag != "" {
+		query += " AND id IN (SELECT timerId FROM timer_tags WHERE tag = ?)"
+		args = append(args, filter.Tag)
+	}
+	if filter.Query != "" {
+		query += ` AND message LIKE ? ESCAPE '\'`
+		args = append(args, "%"+escapeLike(filter.Query)+"%")
+	}
+	if filter.Guild != "" {
+		query += " AND (guild = ?"
+		args = append(args, filter.Guild)
+		if len(filter.GuildChannels) > 0 {
+			query += " OR guild = '' AND channel IN (?" + strings.Repeat(", ?", len(filter.GuildChannels)-1) + ")"
+			for _, channel := range filter.GuildChannels {
+				args = append(args, channel)
+			}
+		}
+		query += ")"
+	}
+	if filter.HidePrivate {
+		query += " AND (private = false OR user = ?)"
+		args = append(args, filter.Viewer)
+	}
+	query += " ORDER BY snoozedDue"
+	return queryTimers(query, args...)
+}
+
+func countActiveTimersForUser(userID string) (int, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM timers WHERE user = ? AND shown = false AND deletedAt IS NULL", userID).Scan(&count)
+	return count, err
 }
 
 func updateTimer(timer *Timer) error {
@@ -103,35 +452,413 @@ func updateTimer(timer *Timer) error {
 	return err
 }
 
+// deleteTimer only marks the timer as deleted so that it can be restored
+// until purgeDeletedTimers removes it for good
 func deleteTimer(id string) error {
-	_, err := db.Exec("DELETE FROM timers WHERE id = ?", id)
+	_, err := db.Exec("UPDATE timers SET deletedAt = ? WHERE id = ? AND deletedAt IS NULL", time.Now(), id)
 	return err
 }
 
-func snoozeTimer(id string, newDueDate time.Time) error {
-	_, err := db.Exec("UPDATE timers SET snoozedDue = ?, snoozeCount = snoozeCount + 1, shown = false WHERE id = ?", newDueDate, id)
+func restoreTimer(id string) error {
+	_, err := db.Exec("UPDATE timers SET deletedAt = NULL WHERE id = ?", id)
 	return err
 }
 
-func getDueTimers() ([]*Timer, error) {
-	rows, err := db.Query("SELECT internalId, id, message, user, channel, creation, due, snoozedDue, snoozeCount, shown FROM timers WHERE snoozedDue <= ? AND shown = false", time.Now())
+// RetentionPolicy selects the shown timers removed by removeShownTimers
+type RetentionPolicy struct {
+	// ShownBefore removes timers shown before this time, zero disables it
+	ShownBefore time.Time
+	// KeepPerUser removes all but the most recent shown timers of each user,
+	// 0 disables it
+	KeepPerUser int
+	// Archive copies the timers to timers_archive, and their snoozes and tags
+	// to snoozes_archive and timer_tags_archive, before they are removed
+	Archive bool
+}
+
+// removeShownTimers applies the retention policy and returns how many timers
+// were removed
+func removeShownTimers(policy RetentionPolicy, now time.Time) (int64, error) {
+	if policy.ShownBefore.IsZero() && policy.KeepPerUser == 0 {
+		return 0, nil
+	}
+
+	selection := `
+		SELECT internalId FROM (
+			SELECT internalId, snoozedDue, ROW_NUMBER() OVER (PARTITION BY user ORDER BY snoozedDue DESC) AS position
+			FROM timers
+			WHERE shown = true AND deletedAt IS NULL
+		)
+		WHERE (? AND snoozedDue < ?) OR (? > 0 AND position > ?)`
+	args := []any{!policy.ShownBefore.IsZero(), policy.ShownBefore, policy.KeepPerUser, policy.KeepPerUser}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	where := "internalId IN (" + selection + ")"
+	if policy.Archive {
+		err = archiveTimerRows(tx, where, args, now)
+	}
+	var result sql.Result
+	if err == nil {
+		result, err = deleteTimerRows(tx, where, args)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// clearExpiredTimers deletes all shown timers of a user and returns them
+func clearExpiredTimers(userID string) ([]*Timer, error) {
+	timers, err := queryTimers("SELECT "+timerColumns+" FROM timers WHERE user = ? AND shown = true AND deletedAt IS NULL", userID)
 	if err != nil {
 		return nil, err
 	}
+	_, err = db.Exec("UPDATE timers SET deletedAt = ? WHERE user = ? AND shown = true AND deletedAt IS NULL", time.Now(), userID)
+	if err != nil {
+		return nil, err
+	}
+	return timers, nil
+}
+
+// purgeDeletedTimers removes the timers deleted before the given time and
+// returns how many there were
+func purgeDeletedTimers(before time.Time) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	result, err := deleteTimerRows(tx, "deletedAt < ?", []any{before})
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// archiveTimerRows copies the timers matching where with their snoozes and
+// tags to the archive tables
+func archiveTimerRows(tx *sql.Tx, where string, args []any, now time.Time) error {
+	_, err := tx.Exec("INSERT OR REPLACE INTO timers_archive ("+timerColumns+", archivedAt) SELECT "+timerColumns+", ? FROM timers WHERE "+where, append([]any{now}, args...)...)
+	if err == nil {
+		_, err = tx.Exec("INSERT OR REPLACE INTO snoozes_archive (internalId, timerId, fromDue, toDue, snoozedAt, user) SELECT internalId, timerId, fromDue, toDue, snoozedAt, user FROM snoozes WHERE timerId IN (SELECT id FROM timers WHERE "+where+")", args...)
+	}
+	if err == nil {
+		_, err = tx.Exec("INSERT OR REPLACE INTO timer_tags_archive (timerId, tag) SELECT timerId, tag FROM timer_tags WHERE timerId IN (SELECT id FROM timers WHERE "+where+")", args...)
+	}
+	return err
+}
+
+// deleteTimerRows removes the timers matching where for good, together with
+// their snoozes and tags. Their events stay, the history of a timer is
+// append-only.
+func deleteTimerRows(tx *sql.Tx, where string, args []any) (sql.Result, error) {
+	for _, table := range []string{"snoozes", "timer_tags"} {
+		_, err := tx.Exec("DELETE FROM "+table+" WHERE timerId IN (SELECT id FROM timers WHERE "+where+")", args...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tx.Exec("DELETE FROM timers WHERE "+where, args...)
+}
+
+// escapeLike makes s match literally in a LIKE pattern with ESCAPE '\'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// setTimerTags replaces the tags of a timer
+func setTimerTags(timerID string, tags []string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM timer_tags WHERE timerId = ?", timerID)
+	for _, tag := range tags {
+		if err != nil {
+			break
+		}
+		_, err = tx.Exec("INSERT INTO timer_tags (timerId, tag) VALUES (?, ?)", timerID, tag)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// attachTags loads the tags of the timers in one query
+func attachTags(timers []*Timer) error {
+	if len(timers) == 0 {
+		return nil
+	}
+	byID := make(map[string]*Timer, len(timers))
+	args := make([]any, len(timers))
+	for i, timer := range timers {
+		byID[timer.ID] = timer
+		args[i] = timer.ID
+	}
+
+	rows, err := db.Query("SELECT timerId, tag FROM timer_tags WHERE timerId IN (?"+strings.Repeat(", ?", len(timers)-1)+") ORDER BY tag", args...)
+	if err != nil {
+		return err
+	}
 	defer func(rows *sql.Rows) {
 		err = rows.Close()
 	}(rows)
 
-	var timers []*Timer
 	for rows.Next() {
-		timer := &Timer{}
-		err := rows.Scan(&timer.InternalID, &timer.ID, &timer.Message, &timer.User, &timer.Channel, &timer.Created, &timer.Due, &timer.SnoozedDue, &timer.SnoozeCount, &timer.Shown)
+		var timerID, tag string
+		if err := rows.Scan(&timerID, &tag); err != nil {
+			return err
+		}
+		byID[timerID].Tags = append(byID[timerID].Tags, tag)
+	}
+	return rows.Err()
+}
+
+// getTagsForUser returns the tags of the user's timers, the most used first
+func getTagsForUser(userID string) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT tag FROM timer_tags
+		JOIN timers ON timers.id = timer_tags.timerId
+		WHERE timers.user = ? AND timers.deletedAt IS NULL
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+	}(rows)
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// deleteOrphanedTags removes the tags of timers that no longer exist
+func deleteOrphanedTags() error {
+	_, err := db.Exec("DELETE FROM timer_tags WHERE timerId NOT IN (SELECT id FROM timers)")
+	return err
+}
+
+// snoozeTimer moves a timer to a new due date and records the snooze in the
+// history of the timer
+func snoozeTimer(id string, newDueDate time.Time, userID string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	var fromDue time.Time
+	err = tx.QueryRow("SELECT snoozedDue FROM timers WHERE id = ?", id).Scan(&fromDue)
+	if err == nil {
+		_, err = tx.Exec("INSERT INTO snoozes (timerId, fromDue, toDue, snoozedAt, user) VALUES (?, ?, ?, ?, ?)", id, fromDue, newDueDate, time.Now(), userID)
+	}
+	if err == nil {
+		_, err = tx.Exec("UPDATE timers SET snoozedDue = ?, snoozeCount = snoozeCount + 1, shown = false, "+resetAlerting+" WHERE id = ?", newDueDate, id)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Snooze is one entry in the snooze history of a timer
+type Snooze struct {
+	TimerID   string
+	From      time.Time
+	To        time.Time
+	SnoozedAt time.Time
+	User      string
+}
+
+func getSnoozeHistory(timerID string) ([]Snooze, error) {
+	rows, err := db.Query("SELECT timerId, fromDue, toDue, snoozedAt, user FROM snoozes WHERE timerId = ? ORDER BY snoozedAt", timerID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+	}(rows)
+
+	var snoozes []Snooze
+	for rows.Next() {
+		var snooze Snooze
+		err := rows.Scan(&snooze.TimerID, &snooze.From, &snooze.To, &snooze.SnoozedAt, &snooze.User)
 		if err != nil {
 			return nil, err
 		}
-		timers = append(timers, timer)
+		snoozes = append(snoozes, snooze)
 	}
-	return timers, nil
+	return snoozes, rows.Err()
+}
+
+func insertTimerEvent(event *TimerEvent) error {
+	details, err := encodeEventDetails(event.Details)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(
+		"INSERT INTO timer_events (timerId, owner, event, actor, time, details) VALUES (?, ?, ?, ?, ?, ?)",
+		event.TimerID, event.Owner, event.Kind, event.Actor, event.Time, details,
+	)
+	return err
+}
+
+// getTimerEvents returns the history of a timer in the order it happened.
+// The owner is part of the lookup since the history outlives the timer.
+func getTimerEvents(timerID string, owner string) ([]*TimerEvent, error) {
+	rows, err := db.Query("SELECT timerId, owner, event, actor, time, details FROM timer_events WHERE timerId = ? AND owner = ? ORDER BY internalId", timerID, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+	}(rows)
+
+	var events []*TimerEvent
+	for rows.Next() {
+		event := &TimerEvent{}
+		var details string
+		err := rows.Scan(&event.TimerID, &event.Owner, &event.Kind, &event.Actor, &event.Time, &details)
+		if err != nil {
+			return nil, err
+		}
+		event.Details, err = decodeEventDetails(details)
+		if err != nil {
+			return nil, fmt.Errorf("decoding details of %s event of timer %s: %w", event.Kind, event.TimerID, err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// getCommonSnoozeDurations returns how far ahead of the moment of snoozing a
+// user's recent snoozes went, the most frequent first. Durations are rounded
+// to whole minutes so that snoozes typed the same way are counted together.
+func getCommonSnoozeDurations(userID string, limit int) ([]time.Duration, error) {
+	rows, err := db.Query("SELECT snoozedAt, toDue FROM snoozes WHERE user = ? ORDER BY snoozedAt DESC LIMIT 200", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+	}(rows)
+
+	var durations []time.Duration
+	for rows.Next() {
+		var snoozedAt, toDue time.Time
+		err := rows.Scan(&snoozedAt, &toDue)
+		if err != nil {
+			return nil, err
+		}
+		durations = append(durations, toDue.Sub(snoozedAt))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return mostCommonDurations(durations, limit), nil
+}
+
+func getDueTimers() ([]*Timer, error) {
+	return queryTimers("SELECT "+timerColumns+" FROM timers WHERE snoozedDue <= ? AND shown = false AND deletedAt IS NULL", time.Now())
+}
+
+func rescheduleTimer(id string, due time.Time) error {
+	_, err := db.Exec("UPDATE timers SET due = ?, snoozedDue = ?, shown = false, "+resetAlerting+" WHERE id = ?", due, due, id)
+	return err
+}
+
+type TimerStats struct {
+	Total    int
+	Active   int
+	Overdue  int
+	Users    int
+	Channels int
+	Snoozes  int
+}
+
+func getTimerStats() (*TimerStats, error) {
+	stats := &TimerStats{}
+	err := db.QueryRow(`
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(shown = false), 0),
+			COALESCE(SUM(shown = false AND snoozedDue <= ?), 0),
+			COUNT(DISTINCT user),
+			COUNT(DISTINCT channel),
+			COALESCE(SUM(snoozeCount), 0)
+		FROM timers
+		WHERE deletedAt IS NULL
+	`, time.Now()).Scan(&stats.Total, &stats.Active, &stats.Overdue, &stats.Users, &stats.Channels, &stats.Snoozes)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func vacuumDB() error {
+	_, err := db.Exec("VACUUM")
+	return err
+}
+
+// clearFollowUps removes the remaining steps of a timer once the next link of
+// its chain was created
+func clearFollowUps(id string) error {
+	_, err := db.Exec("UPDATE timers SET followUps = '' WHERE id = ?", id)
+	return err
+}
+
+// resetAlerting starts the acknowledgement and quiet hours deferral of a
+// timer over when it is due again after a snooze or reschedule
+const resetAlerting = "acknowledgedAt = NULL, lastPing = NULL, escalatedAt = NULL, deferredFrom = NULL"
+
+// getUnacknowledgedTimers returns the important timers that were shown since
+// the given time and not acknowledged yet. Timers still waiting for their
+// escalation are returned however long ago they were shown, as escalations
+// may be set up to happen days later.
+func getUnacknowledgedTimers(shownSince time.Time) ([]*Timer, error) {
+	return queryTimers("SELECT "+timerColumns+" FROM timers WHERE shown = true AND acknowledgedAt IS NULL AND repingInterval > 0 AND deletedAt IS NULL AND (snoozedDue >= ? OR escalateTo != '' AND escalatedAt IS NULL)", shownSince)
+}
+
+func acknowledgeTimer(id string, now time.Time) error {
+	_, err := db.Exec("UPDATE timers SET acknowledgedAt = ? WHERE id = ?", now, id)
+	return err
+}
+
+func setTimerPinged(id string, now time.Time) error {
+	_, err := db.Exec("UPDATE timers SET lastPing = ? WHERE id = ?", now, id)
+	return err
+}
+
+func setTimerEscalated(id string, now time.Time) error {
+	_, err := db.Exec("UPDATE timers SET escalatedAt = ? WHERE id = ?", now, id)
+	return err
+}
+
+// deferTimer moves a due timer to the end of the quiet hours of its owner,
+// deferredFrom keeps the time it was due at before the first deferral
+func deferTimer(id string, until time.Time, from time.Time) error {
+	_, err := db.Exec("UPDATE timers SET snoozedDue = ?, deferredFrom = COALESCE(deferredFrom, ?) WHERE id = ?", until, from, id)
+	return err
 }
 
 func markTimerAsShown(id string) error {
@@ -139,18 +866,328 @@ func markTimerAsShown(id string) error {
 	return err
 }
 
+// stopwatchColumns lists the columns of the stopwatches table in the order scanStopwatch expects them
+const stopwatchColumns = "internalId, id, name, user, channel, started, pausedAt, pausedDuration, stopped, laps, language"
+
+func scanStopwatch(row rowScanner) (*Stopwatch, error) {
+	stopwatch := &Stopwatch{}
+	var pausedAt, stopped sql.NullTime
+	var laps string
+	err := row.Scan(&stopwatch.InternalID, &stopwatch.ID, &stopwatch.Name, &stopwatch.User, &stopwatch.Channel, &stopwatch.Started, &pausedAt, &stopwatch.PausedDuration, &stopped, &laps, &stopwatch.Language)
+	if err != nil {
+		return nil, err
+	}
+	if pausedAt.Valid {
+		stopwatch.PausedAt = &pausedAt.Time
+	}
+	if stopped.Valid {
+		stopwatch.Stopped = &stopped.Time
+	}
+	stopwatch.Laps, err = decodeLaps(laps)
+	if err != nil {
+		return nil, fmt.Errorf("decoding laps of stopwatch %s: %w", stopwatch.ID, err)
+	}
+	return stopwatch, nil
+}
+
+func createStopwatch(id string, name string, userID string, channelID string, language string) (*Stopwatch, error) {
+	stopwatch := &Stopwatch{
+		ID:       id,
+		Name:     name,
+		User:     userID,
+		Channel:  channelID,
+		Started:  time.Now(),
+		Language: language,
+	}
+	_, err := db.Exec("INSERT INTO stopwatches (id, name, user, channel, started, language) VALUES (?, ?, ?, ?, ?, ?)", stopwatch.ID, stopwatch.Name, stopwatch.User, stopwatch.Channel, stopwatch.Started, stopwatch.Language)
+	if err != nil {
+		return nil, err
+	}
+	return stopwatch, nil
+}
+
+func getStopwatchByID(id string) (*Stopwatch, error) {
+	return scanStopwatch(db.QueryRow("SELECT "+stopwatchColumns+" FROM stopwatches WHERE id = ?", id))
+}
+
+func getStopwatchesForUser(userID string, includeStopped bool) ([]*Stopwatch, error) {
+	query := "SELECT " + stopwatchColumns + " FROM stopwatches WHERE user = ?"
+	if !includeStopped {
+		query += " AND stopped IS NULL"
+	}
+	rows, err := db.Query(query+" ORDER BY started", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+	}(rows)
+
+	var stopwatches []*Stopwatch
+	for rows.Next() {
+		stopwatch, err := scanStopwatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		stopwatches = append(stopwatches, stopwatch)
+	}
+	return stopwatches, rows.Err()
+}
+
+// updateStopwatch stores the state of a stopwatch after a pause, resume, lap or stop
+func updateStopwatch(stopwatch *Stopwatch) error {
+	laps, err := encodeLaps(stopwatch.Laps)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("UPDATE stopwatches SET pausedAt = ?, pausedDuration = ?, stopped = ?, laps = ? WHERE id = ?", stopwatch.PausedAt, stopwatch.PausedDuration, stopwatch.Stopped, laps, stopwatch.ID)
+	return err
+}
+
+// pomodoroColumns lists the columns of the pomodoros table in the order scanPomodoro expects them
+const pomodoroColumns = "internalId, id, user, channel, workLength, breakLength, longBreakLength, cycles, phase, state, timerId, phaseDue, remaining, completedCycles, started, finished, language"
+
+func scanPomodoro(row rowScanner) (*Pomodoro, error) {
+	pomodoro := &Pomodoro{}
+	var phaseDue, finished sql.NullTime
+	err := row.Scan(&pomodoro.InternalID, &pomodoro.ID, &pomodoro.User, &pomodoro.Channel, &pomodoro.WorkLength, &pomodoro.BreakLength, &pomodoro.LongBreakLength, &pomodoro.Cycles, &pomodoro.Phase, &pomodoro.State, &pomodoro.TimerID, &phaseDue, &pomodoro.Remaining, &pomodoro.CompletedCycles, &pomodoro.Started, &finished, &pomodoro.Language)
+	if err != nil {
+		return nil, err
+	}
+	pomodoro.PhaseDue = phaseDue.Time
+	if finished.Valid {
+		pomodoro.Finished = &finished.Time
+	}
+	return pomodoro, nil
+}
+
+func createPomodoro(pomodoro *Pomodoro) error {
+	_, err := db.Exec(
+		"INSERT INTO pomodoros (id, user, channel, workLength, breakLength, longBreakLength, cycles, state, started, language) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		pomodoro.ID, pomodoro.User, pomodoro.Channel, pomodoro.WorkLength, pomodoro.BreakLength, pomodoro.LongBreakLength, pomodoro.Cycles, pomodoro.State, pomodoro.Started, pomodoro.Language,
+	)
+	return err
+}
+
+func getPomodoroByID(id string) (*Pomodoro, error) {
+	return scanPomodoro(db.QueryRow("SELECT "+pomodoroColumns+" FROM pomodoros WHERE id = ?", id))
+}
+
+func updatePomodoro(pomodoro *Pomodoro) error {
+	_, err := db.Exec(
+		"UPDATE pomodoros SET phase = ?, state = ?, timerId = ?, phaseDue = ?, remaining = ?, completedCycles = ?, finished = ? WHERE id = ?",
+		pomodoro.Phase, pomodoro.State, pomodoro.TimerID, pomodoro.PhaseDue, pomodoro.Remaining, pomodoro.CompletedCycles, pomodoro.Finished, pomodoro.ID,
+	)
+	return err
+}
+
+type PomodoroStats struct {
+	Finished        int
+	Stopped         int
+	CompletedCycles int
+	WorkTime        time.Duration
+}
+
+// getPomodoroStats sums up the sessions of a user
+func getPomodoroStats(userID string) (*PomodoroStats, error) {
+	stats := &PomodoroStats{}
+	err := db.QueryRow(`
+		SELECT
+			COALESCE(SUM(state = ?), 0),
+			COALESCE(SUM(state = ?), 0),
+			COALESCE(SUM(completedCycles), 0),
+			COALESCE(SUM(completedCycles * workLength), 0)
+		FROM pomodoros
+		WHERE user = ?
+	`, PomodoroStateFinished, PomodoroStateStopped, userID).Scan(&stats.Finished, &stats.Stopped, &stats.CompletedCycles, &stats.WorkTime)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func newPomodoroID() (string, error) {
+	return newUniqueID(func(id string) error {
+		_, err := getPomodoroByID(id)
+		return err
+	})
+}
+
+// Setting scopes, user settings take precedence over guild settings
+const (
+	SettingScopeUser  = "user"
+	SettingScopeGuild = "guild"
+)
+
+func getSetting(scope string, scopeID string, key string) (string, bool, error) {
+	var value string
+	err := db.QueryRow("SELECT value FROM settings WHERE scope = ? AND scopeId = ? AND key = ?", scope, scopeID, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func setSetting(scope string, scopeID string, key string, value string) error {
+	_, err := db.Exec("INSERT INTO settings (scope, scopeId, key, value) VALUES (?, ?, ?, ?) ON CONFLICT (scope, scopeId, key) DO UPDATE SET value = excluded.value", scope, scopeID, key, value)
+	return err
+}
+
+func deleteSetting(scope string, scopeID string, key string) error {
+	_, err := db.Exec("DELETE FROM settings WHERE scope = ? AND scopeId = ? AND key = ?", scope, scopeID, key)
+	return err
+}
+
+const templateColumns = "scope, scopeId, name, message, time, channel, followUps, tags, important"
+
+func scanTemplate(row rowScanner) (*TimerTemplate, error) {
+	template := &TimerTemplate{}
+	var tags string
+	err := row.Scan(&template.Scope, &template.ScopeID, &template.Name, &template.Message, &template.Time, &template.Channel, &template.FollowUps, &tags, &template.Important)
+	if err != nil {
+		return nil, err
+	}
+	if tags != "" {
+		template.Tags = strings.Split(tags, ",")
+	}
+	return template, nil
+}
+
+// saveTemplate stores a template, replacing the one with the same name
+func saveTemplate(template *TimerTemplate) error {
+	_, err := db.Exec(
+		"INSERT OR REPLACE INTO timer_templates ("+templateColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		template.Scope, template.ScopeID, template.Name, template.Message, template.Time, template.Channel, template.FollowUps, strings.Join(template.Tags, ","), template.Important,
+	)
+	return err
+}
+
+func getTemplate(scope string, scopeID string, name string) (*TimerTemplate, error) {
+	return scanTemplate(db.QueryRow("SELECT "+templateColumns+" FROM timer_templates WHERE scope = ? AND scopeId = ? AND name = ?", scope, scopeID, name))
+}
+
+func getTemplates(scope string, scopeID string) ([]*TimerTemplate, error) {
+	rows, err := db.Query("SELECT "+templateColumns+" FROM timer_templates WHERE scope = ? AND scopeId = ? ORDER BY name", scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+	}(rows)
+
+	var templates []*TimerTemplate
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+// deleteTemplate removes a template and reports whether it existed
+func deleteTemplate(scope string, scopeID string, name string) (bool, error) {
+	result, err := db.Exec("DELETE FROM timer_templates WHERE scope = ? AND scopeId = ? AND name = ?", scope, scopeID, name)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	return count > 0, err
+}
+
+const boardColumns = "channel, guild, messageId, scope, size, language"
+
+func scanBoard(row rowScanner) (*TimerBoard, error) {
+	board := &TimerBoard{}
+	err := row.Scan(&board.Channel, &board.Guild, &board.MessageID, &board.Scope, &board.Size, &board.Language)
+	if err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// saveBoard stores a board, replacing the one the channel had before
+func saveBoard(board *TimerBoard) error {
+	_, err := db.Exec(
+		"INSERT OR REPLACE INTO timer_boards ("+boardColumns+") VALUES (?, ?, ?, ?, ?, ?)",
+		board.Channel, board.Guild, board.MessageID, board.Scope, board.Size, board.Language,
+	)
+	return err
+}
+
+// getBoard returns sql.ErrNoRows if the channel has no board
+func getBoard(channelID string) (*TimerBoard, error) {
+	return scanBoard(db.QueryRow("SELECT "+boardColumns+" FROM timer_boards WHERE channel = ?", channelID))
+}
+
+func getBoards() ([]*TimerBoard, error) {
+	rows, err := db.Query("SELECT " + boardColumns + " FROM timer_boards")
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+	}(rows)
+
+	var boards []*TimerBoard
+	for rows.Next() {
+		board, err := scanBoard(rows)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, board)
+	}
+	return boards, rows.Err()
+}
+
+func deleteBoard(channelID string) error {
+	_, err := db.Exec("DELETE FROM timer_boards WHERE channel = ?", channelID)
+	return err
+}
+
+// newTimerID derives the ID from a sequence of the configured scheme, which
+// never hands out the same value twice, so there is no need to check for
+// collisions
 func newTimerID() (string, error) {
+	scheme := config.IDs.Scheme
+	n, err := nextSequenceValue("timer_" + scheme)
+	if err != nil {
+		return "", err
+	}
+	return encodeTimerID(scheme, n)
+}
+
+// nextSequenceValue counts up from 0, separately for each name
+func nextSequenceValue(name string) (uint64, error) {
+	var value uint64
+	err := db.QueryRow("INSERT INTO sequences (name, value) VALUES (?, 0) ON CONFLICT (name) DO UPDATE SET value = value + 1 RETURNING value", name).Scan(&value)
+	return value, err
+}
+
+func newStopwatchID() (string, error) {
+	return newUniqueID(func(id string) error {
+		_, err := getStopwatchByID(id)
+		return err
+	})
+}
+
+// newUniqueID generates random IDs until lookup reports that none exists yet
+func newUniqueID(lookup func(id string) error) (string, error) {
 	for {
 		id := randomString(4)
 
-		_, err := getTimerByID(id)
+		err := lookup(id)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return id, nil // ID is unique
 			}
 			return "", err // Other database error
 		}
-		// If no error, timer with this ID already exists, loop again
+		// If no error, something with this ID already exists, loop again
 	}
 }
 
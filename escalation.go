@@ -0,0 +1,192 @@
+package main
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultRepingInterval = 15 * time.Minute
+	defaultEscalateAfter  = time.Hour
+	// repingWindow stops the re-pings of a timer nobody acknowledges
+	repingWindow = 24 * time.Hour
+)
+
+// pendingAlerts tells what is due for an unacknowledged important timer
+func pendingAlerts(timer *Timer, now time.Time) (reping bool, escalate bool) {
+	if timer.AcknowledgedAt != nil || timer.RepingInterval <= 0 {
+		return false, false
+	}
+
+	lastPing := timer.SnoozedDue
+	if timer.LastPing != nil {
+		lastPing = *timer.LastPing
+	}
+	reping = !now.Before(lastPing.Add(timer.RepingInterval)) && now.Before(timer.SnoozedDue.Add(repingWindow))
+	escalate = timer.EscalateTo != "" && timer.EscalatedAt == nil && !now.Before(timer.SnoozedDue.Add(timer.EscalateAfter))
+	return reping, escalate
+}
+
+// checkUnacknowledgedTimers runs after checkDueTimers and pings the owners
+// of important timers again, or their backup once the deadline passed
+func checkUnacknowledgedTimers(session *discordgo.Session, now time.Time) {
+	timers, err := getUnacknowledgedTimers(now.Add(-repingWindow))
+	if err != nil {
+		slog.Error("Getting unacknowledged timers", "error", err)
+		return
+	}
+
+	for _, timer := range timers {
+		reping, escalate := pendingAlerts(timer, now)
+		// Escalations go to someone else, only the owner's re-pings wait
+		if reping {
+			if _, quiet := getQuietSettings(timer.User, timer.Guild).QuietUntil(now); quiet {
+				reping = false
+			}
+		}
+		if escalate {
+			escalateTimer(session, timer, now)
+		} else if reping {
+			sendAlert(session, timer, TimerEventRepinged, now)
+		}
+		if reping || escalate {
+			if err := setTimerPinged(timer.ID, now); err != nil {
+				slog.Error("Marking timer as pinged", "error", err)
+			}
+		}
+	}
+}
+
+// escalateTimer hands a timer over to its backup, it is only done once
+func escalateTimer(session *discordgo.Session, timer *Timer, now time.Time) {
+	sendAlert(session, timer, TimerEventEscalated, now)
+	if err := setTimerEscalated(timer.ID, now); err != nil {
+		slog.Error("Marking timer as escalated", "error", err)
+	}
+}
+
+// sendAlert pings the owner of a timer again, or its backup for an escalation
+func sendAlert(session *discordgo.Session, timer *Timer, kind string, now time.Time) {
+	user, err := session.User(timer.User)
+	if err != nil {
+		slog.Error("Getting user", "error", err)
+		recordTimerEvent(timer, TimerEventDeliveryFailed, "", map[string]string{"error": err.Error()})
+		return
+	}
+
+	language := getTimerLanguage(timer)
+	embedType := TimerEmbedTypeReping
+	content := tr(language, "ack.reping", user.Mention(), humanizeDelay(now.Sub(timer.SnoozedDue).Round(time.Minute), language))
+	if kind == TimerEventEscalated {
+		embedType = TimerEmbedTypeUnacknowledged
+		content = tr(language, "ack.escalated", timer.EscalateTo, user.Mention())
+	}
+
+	_, err = session.ChannelMessageSendComplex(timer.Channel, &discordgo.MessageSend{
+		Content:    content,
+		Embeds:     []*discordgo.MessageEmbed{createTimerEmbed(timer, user, embedType, language)},
+		Components: acknowledgeComponents(timer, language),
+	})
+	if err != nil {
+		slog.Error("Sending alert", "error", err)
+		recordTimerEvent(timer, TimerEventDeliveryFailed, "", map[string]string{"error": err.Error()})
+		return
+	}
+	recordTimerEvent(timer, kind, "", map[string]string{"channel": timer.Channel})
+}
+
+// acknowledgeComponents is the button confirming that a due timer was seen
+func acknowledgeComponents(timer *Timer, language string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    tr(language, "ack.button"),
+					Style:    discordgo.SuccessButton,
+					CustomID: "timer_ack:" + timer.ID,
+				},
+			},
+		},
+	}
+}
+
+// canAcknowledge allows the owner and, for escalating timers, the backup
+// user or the members of the backup role
+func canAcknowledge(timer *Timer, userID string, roles []string) bool {
+	if userID == timer.User {
+		return true
+	}
+	switch {
+	case strings.HasPrefix(timer.EscalateTo, "<@&"):
+		return slices.Contains(roles, strings.Trim(timer.EscalateTo, "<@&>"))
+	case strings.HasPrefix(timer.EscalateTo, "<@"):
+		return strings.Trim(timer.EscalateTo, "<@>") == userID
+	}
+	return false
+}
+
+// handleTimerAcknowledge handles the button on due timers and alerts,
+// argument is the ID of the timer
+func handleTimerAcknowledge(session *discordgo.Session, interaction *discordgo.InteractionCreate, argument string) {
+	language := getInteractionLanguage(interaction)
+	user := getUserFromInteraction(interaction)
+
+	timer, err := getTimerByID(argument)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.invalid_timer_id"), "handleTimerAcknowledge() invalid timer id", err)
+		return
+	}
+
+	var roles []string
+	if interaction.Member != nil {
+		roles = interaction.Member.Roles
+	}
+	if !canAcknowledge(timer, user.ID, roles) {
+		respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: tr(language, "ack.not_allowed"),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		}, "handleTimerAcknowledge() not allowed")
+		return
+	}
+
+	if timer.AcknowledgedAt == nil {
+		now := time.Now()
+		err = acknowledgeTimer(timer.ID, now)
+		if err != nil {
+			respondWithError(session, interaction.Interaction, tr(language, "error.updating_timer"), "handleTimerAcknowledge() error acknowledging timer", err)
+			return
+		}
+		timer.AcknowledgedAt = &now
+		recordTimerEvent(timer, TimerEventAcknowledged, user.ID, nil)
+	}
+
+	owner, err := session.User(timer.User)
+	if err != nil {
+		owner = &discordgo.User{ID: timer.User}
+	}
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    tr(language, "ack.done", user.Mention()),
+			Embeds:     []*discordgo.MessageEmbed{createTimerEmbed(timer, owner, TimerEmbedTypeAcknowledged, language)},
+			Components: []discordgo.MessageComponent{},
+		},
+	}, "handleTimerAcknowledge() success case")
+}
+
+// describeAlerting summarizes the re-ping and escalation settings of an
+// important timer for its embed
+func describeAlerting(timer *Timer, language string) string {
+	description := tr(language, "ack.reping_every", humanizeDelay(timer.RepingInterval, language))
+	if timer.EscalateTo != "" {
+		description += "\n" + tr(language, "ack.escalate_to", timer.EscalateTo, humanizeDelay(timer.EscalateAfter, language))
+	}
+	return description
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingAlerts(t *testing.T) {
+	due := time.Date(2030, time.January, 1, 10, 0, 0, 0, time.UTC)
+	timer := &Timer{SnoozedDue: due, RepingInterval: 15 * time.Minute, EscalateTo: "<@&7>", EscalateAfter: time.Hour}
+
+	reping, escalate := pendingAlerts(timer, due.Add(10*time.Minute))
+	assert.False(t, reping)
+	assert.False(t, escalate)
+
+	reping, escalate = pendingAlerts(timer, due.Add(15*time.Minute))
+	assert.True(t, reping)
+	assert.False(t, escalate)
+
+	lastPing := due.Add(15 * time.Minute)
+	timer.LastPing = &lastPing
+	reping, _ = pendingAlerts(timer, due.Add(20*time.Minute))
+	assert.False(t, reping)
+
+	_, escalate = pendingAlerts(timer, due.Add(time.Hour))
+	assert.True(t, escalate)
+
+	escalated := due.Add(time.Hour)
+	timer.EscalatedAt = &escalated
+	_, escalate = pendingAlerts(timer, due.Add(2*time.Hour))
+	assert.False(t, escalate)
+
+	reping, _ = pendingAlerts(timer, due.Add(repingWindow))
+	assert.False(t, reping)
+
+	acknowledged := due.Add(16 * time.Minute)
+	timer.AcknowledgedAt = &acknowledged
+	reping, escalate = pendingAlerts(timer, due.Add(time.Hour))
+	assert.False(t, reping)
+	assert.False(t, escalate)
+
+	reping, escalate = pendingAlerts(&Timer{SnoozedDue: due}, due.Add(time.Hour))
+	assert.False(t, reping)
+	assert.False(t, escalate)
+}
+
+func TestCanAcknowledge(t *testing.T) {
+	timer := &Timer{User: "1"}
+	assert.True(t, canAcknowledge(timer, "1", nil))
+	assert.False(t, canAcknowledge(timer, "2", nil))
+
+	timer.EscalateTo = "<@2>"
+	assert.True(t, canAcknowledge(timer, "2", nil))
+	assert.False(t, canAcknowledge(timer, "3", []string{"2"}))
+
+	timer.EscalateTo = "<@&5>"
+	assert.True(t, canAcknowledge(timer, "3", []string{"4", "5"}))
+	assert.False(t, canAcknowledge(timer, "3", []string{"4"}))
+	assert.False(t, canAcknowledge(timer, "5", nil))
+}
+
+func TestEscalationAfterRepingWindow(t *testing.T) {
+	require.NoError(t, initDB(filepath.Join(t.TempDir(), "escalation.db")))
+	now := time.Now()
+	options := TimerOptions{RepingInterval: defaultRepingInterval, EscalateTo: "<@2>", EscalateAfter: 30 * time.Hour}
+	_, err := createTimer("a", "Renew the certificate", "1", "c1", now.Add(-25*time.Hour), LanguageEnglish, options)
+	require.NoError(t, err)
+	require.NoError(t, markTimerAsShown("a"))
+
+	// Re-pings stopped after a day, the escalation is still to come
+	timers, err := getUnacknowledgedTimers(now.Add(-repingWindow))
+	require.NoError(t, err)
+	require.Len(t, timers, 1)
+	reping, escalate := pendingAlerts(timers[0], now)
+	assert.False(t, reping)
+	assert.False(t, escalate)
+
+	later := now.Add(5 * time.Hour)
+	timers, err = getUnacknowledgedTimers(later.Add(-repingWindow))
+	require.NoError(t, err)
+	require.Len(t, timers, 1)
+	reping, escalate = pendingAlerts(timers[0], later)
+	assert.False(t, reping)
+	assert.True(t, escalate)
+
+	// Once escalated the timer is left alone
+	require.NoError(t, setTimerEscalated("a", later))
+	timers, err = getUnacknowledgedTimers(later.Add(-repingWindow))
+	require.NoError(t, err)
+	assert.Empty(t, timers)
+}
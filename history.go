@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	TimerEventCreated        = "created"
+	TimerEventEdited         = "edited"
+	TimerEventSnoozed        = "snoozed"
+	TimerEventDelivered      = "delivered"
+	TimerEventDeliveryFailed = "delivery_failed"
+	TimerEventExpired        = "expired"
+	TimerEventDeleted        = "deleted"
+	TimerEventRestored       = "restored"
+	TimerEventAcknowledged   = "acknowledged"
+	TimerEventRepinged       = "repinged"
+	TimerEventEscalated      = "escalated"
+	TimerEventDeferred       = "deferred"
+)
+
+// TimerEvent is one entry in the append-only history of a timer. Actor is
+// the user who caused the event, empty for events caused by the bot itself.
+type TimerEvent struct {
+	TimerID string
+	Owner   string
+	Kind    string
+	Actor   string
+	Time    time.Time
+	// Details depend on the kind, e.g. the old and new values of an edit.
+	// Times are stored as unix timestamps.
+	Details map[string]string
+}
+
+// recordTimerEvent appends an event to the history of the timer. Failing to
+// record it must not fail the action itself, so errors are only logged.
+func recordTimerEvent(timer *Timer, kind string, actor string, details map[string]string) {
+	err := insertTimerEvent(&TimerEvent{
+		TimerID: timer.ID,
+		Owner:   timer.User,
+		Kind:    kind,
+		Actor:   actor,
+		Time:    time.Now(),
+		Details: details,
+	})
+	if err != nil {
+		slog.Error("Recording timer event", "event", kind, "timer", timer.ID, "error", err)
+	}
+	// Every change of a timer passes through here, boards showing it
+	// are updated on the next run of the scheduler
+	markBoardsStale(timer)
+}
+
+func unixDetail(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// editDetails lists the old and new values of everything that changed
+func editDetails(old *Timer, new *Timer) map[string]string {
+	details := make(map[string]string)
+	if old.Message != new.Message {
+		details["oldMessage"] = old.Message
+		details["newMessage"] = new.Message
+	}
+	if !slices.Equal(old.Tags, new.Tags) {
+		details["oldTags"] = strings.Join(old.Tags, ",")
+		details["newTags"] = strings.Join(new.Tags, ",")
+	}
+	if !old.SnoozedDue.Equal(new.SnoozedDue) {
+		details["oldDue"] = unixDetail(old.SnoozedDue)
+		details["newDue"] = unixDetail(new.SnoozedDue)
+	}
+	return details
+}
+
+func encodeEventDetails(details map[string]string) (string, error) {
+	if len(details) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(details)
+	return string(encoded), err
+}
+
+func decodeEventDetails(encoded string) (map[string]string, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	var details map[string]string
+	err := json.Unmarshal([]byte(encoded), &details)
+	return details, err
+}
+
+// describeTimerEvent renders one line of the timeline of /timer history
+func describeTimerEvent(event *TimerEvent, language string) string {
+	actor := tr(language, "history.actor.bot")
+	if event.Actor != "" {
+		actor = "<@" + event.Actor + ">"
+	}
+	line := tr(language, "history.entry", event.Time.Unix(), tr(language, "history.event."+event.Kind), actor)
+
+	details := event.Details
+	var parts []string
+	switch event.Kind {
+	case TimerEventCreated:
+		if details["due"] != "" {
+			parts = append(parts, tr(language, "history.detail.due", details["due"]))
+		}
+	case TimerEventEdited:
+		if _, ok := details["newMessage"]; ok {
+			parts = append(parts, tr(language, "history.detail.message", details["oldMessage"], details["newMessage"]))
+		}
+		if details["newDue"] != "" {
+			parts = append(parts, tr(language, "history.detail.due_changed", details["oldDue"], details["newDue"]))
+		}
+		if oldTags, ok := details["oldTags"]; ok {
+			parts = append(parts, tr(language, "history.detail.tags", describeTagDetail(oldTags), describeTagDetail(details["newTags"])))
+		}
+	case TimerEventSnoozed, TimerEventDeferred:
+		parts = append(parts, tr(language, "history.detail.due_changed", details["oldDue"], details["newDue"]))
+	case TimerEventDelivered, TimerEventRepinged, TimerEventEscalated:
+		parts = append(parts, tr(language, "history.detail.channel", details["channel"]))
+	case TimerEventDeliveryFailed:
+		parts = append(parts, tr(language, "history.detail.error", details["error"]))
+	}
+	if len(parts) > 0 {
+		line += "\n" + strings.Join(parts, "\n")
+	}
+	return line
+}
+
+func describeTagDetail(tags string) string {
+	if tags == "" {
+		return "-"
+	}
+	return formatTags(strings.Split(tags, ","))
+}
+
+// maxHistoryLength keeps the timeline below the embed description limit of
+// 4096 characters, with room left for the line about earlier events
+const maxHistoryLength = 4000
+
+// describeTimerHistory renders the timeline, dropping the oldest events if
+// it gets too long
+func describeTimerHistory(events []*TimerEvent, language string) string {
+	var lines []string
+	length := 0
+	for i := len(events) - 1; i >= 0; i-- {
+		line := describeTimerEvent(events[i], language)
+		if length+len(line)+1 > maxHistoryLength {
+			lines = append(lines, tr(language, "history.more", i+1))
+			break
+		}
+		lines = append(lines, line)
+		length += len(line) + 1
+	}
+
+	// Collected newest first, shown oldest first
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func createTimerHistoryEmbed(timerID string, events []*TimerEvent, language string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       tr(language, "history.title", timerID),
+		Description: describeTimerHistory(events, language),
+		Color:       0x808080,
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditDetails(t *testing.T) {
+	due := time.Date(2030, time.January, 1, 10, 0, 0, 0, time.UTC)
+	old := &Timer{Message: "old", Due: due, SnoozedDue: due}
+
+	changed := *old
+	changed.Message = "new"
+	assert.Equal(t, map[string]string{"oldMessage": "old", "newMessage": "new"}, editDetails(old, &changed))
+
+	changed = *old
+	changed.SnoozedDue = due.Add(time.Hour)
+	assert.Equal(t, map[string]string{"oldDue": "1893492000", "newDue": "1893495600"}, editDetails(old, &changed))
+
+	assert.Empty(t, editDetails(old, old))
+}
+
+func TestEventDetailsRoundTrip(t *testing.T) {
+	encoded, err := encodeEventDetails(nil)
+	require.NoError(t, err)
+	assert.Empty(t, encoded)
+
+	details := map[string]string{"error": "sending message: 403 Forbidden"}
+	encoded, err = encodeEventDetails(details)
+	require.NoError(t, err)
+	decoded, err := decodeEventDetails(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, details, decoded)
+}
+
+func TestDescribeTimerHistory(t *testing.T) {
+	start := time.Date(2030, time.January, 1, 10, 0, 0, 0, time.UTC)
+	events := []*TimerEvent{
+		{Kind: TimerEventCreated, Actor: "42", Time: start, Details: map[string]string{"due": "1893495600"}},
+		{Kind: TimerEventDeliveryFailed, Time: start.Add(time.Hour), Details: map[string]string{"error": "missing access"}},
+	}
+
+	history := describeTimerHistory(events, LanguageEnglish)
+	assert.Less(t, strings.Index(history, "Created"), strings.Index(history, "Delivery failed"))
+	assert.Contains(t, history, "by <@42>")
+	assert.Contains(t, history, "by the bot")
+	assert.Contains(t, history, "missing access")
+
+	for range 200 {
+		events = append(events, &TimerEvent{Kind: TimerEventSnoozed, Actor: "42", Time: start, Details: map[string]string{"oldDue": "1", "newDue": "2"}})
+	}
+	history = describeTimerHistory(events, LanguageEnglish)
+	assert.LessOrEqual(t, len(history), maxHistoryLength)
+	assert.True(t, strings.HasPrefix(history, "…"), history[:20])
+	assert.NotContains(t, history, "Created")
+}
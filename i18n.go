@@ -0,0 +1,864 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	LanguageEnglish = "en"
+	LanguageGerman  = "de"
+)
+
+var supportedLanguages = []string{LanguageEnglish, LanguageGerman}
+
+// messages is the catalog of all user facing strings. English is the
+// fallback for keys that are missing in another language.
+var messages = map[string]map[string]string{
+	LanguageEnglish: {
+		"error.invalid_date":        "Invalid date format",
+		"error.invalid_timer_id":    "Invalid timer ID",
+		"error.not_owner":           "You do not own this timer",
+		"error.pomodoro_timer":      "This timer belongs to a pomodoro, use /pomodoro to control it",
+		"error.message_too_long":    "The message must not be longer than %d characters",
+		"error.timer_limit":         "You can not have more than %d active timers",
+		"error.creating_timer_id":   "Error creating timer ID",
+		"error.creating_timer":      "Error creating timer",
+		"error.getting_timers":      "Error getting timers",
+		"error.deleting_timer":      "Error deleting timer",
+		"error.updating_timer":      "Error updating timer",
+		"error.snoozing_timer":      "Error snoozing timer",
+		"error.getting_timer":       "Error getting snoozed timer",
+		"error.saving_settings":     "Error saving settings",
+		"error.missing_permission":  "You need the Manage Server permission to change server settings",
+		"error.guild_only":          "This can only be used in a server",
+		"error.time_empty":          "Please enter a time, e.g. \"in 2 hours\" or \"tomorrow 9:00\"",
+		"error.time_not_understood": "I could not understand \"%s\" as a time. Try e.g. \"in 2 hours\", \"friday 10:00\" or \"2030-12-24 18:00\"",
+		"error.time_in_past":        "\"%s\" is in the past (%s)",
+		"error.invalid_follow_ups":  "Invalid follow-ups: %s",
+		"error.follow_up_time":      "The time \"%s\" of a follow-up has to be a duration like \"15m\" or \"in 1 hour\", it counts from the previous step",
+		"choice.prompt":             "\"%s\" could mean different times, which one did you mean?",
+		"choice.placeholder":        "Pick a time",
+		"choice.expired":            "This selection has expired, please run the command again",
+		"choice.not_yours":          "Only the person who ran the command can pick the time",
+		"reason.standard_format":    "exact date",
+		"reason.as_written":         "as written",
+		"reason.in_prefix":          "read as \"in …\"",
+		"reason.on_prefix":          "read as \"on …\"",
+		"reason.next_prefix":        "read as \"next …\"",
+		"reason.past":               "as written, in the past",
+		"reason.date_order":         "day and month swapped",
+		"until.title":               "Time until",
+		"until.description":         "Time until <t:%d:F>: %s",
+		"until.business_days":       "Business days remaining: %d",
+		"list.no_active_timers":     "You have no active timers.",
+		"list.no_timers":            "You have no timers.",
+		"list.active_title":         "Active Timers",
+		"list.all_title":            "All Timers",
+		"list.entry":                "%s - Due: <t:%d:R>",
+		"list.more":                 "… and %d more",
+		"list.scope.channel":        "in this Channel",
+		"list.scope.guild":          "on this Server",
+		"list.empty.channel":        "There are no timers in this channel.",
+		"list.empty.guild":          "There are no timers on this server.",
+		"list.owner":                "By <@%s>",
+		"list.private":              "Private",
+		"search.title":              "Timers Containing \"%s\"",
+		"search.no_results":         "None of your timers contain \"%s\".",
+		"embed.created":             "Timer Created",
+		"embed.deleted":             "Timer Deleted",
+		"embed.snoozed":             "Timer Snoozed",
+		"embed.edited":              "Timer Edited",
+		"embed.due":                 "Timer Due",
+		"embed.field.id":            "Id",
+		"embed.field.owner":         "Owner",
+		"embed.field.due":           "Due",
+		"embed.field.created":       "Created",
+		"embed.field.chain":         "Chain",
+		"embed.field.follow_ups":    "Follow-ups",
+		"embed.follow_up":           "Follow-up Timer Started",
+		"chain.position":            "Step %d of %d",
+		"chain.step":                "after %s: %s",
+		"preview.invalid":           "%s → not understood",
+		"settings.user_title":       "Your Settings",
+		"settings.guild_title":      "Server Settings",
+		"settings.language":         "Language",
+		"settings.date_order":       "Date order",
+		"settings.date_languages":   "Date languages",
+		"settings.default_time":     "Default time",
+		"settings.default_time.now": "current time",
+		"settings.named_times":      "Named times",
+		"settings.weekend":          "Weekend",
+		"settings.weekend.none":     "none",
+		"settings.holidays":         "Holidays",
+		"settings.not_set":          "not set",
+		"settings.saved":            "Settings saved",
+		"settings.language.english": "English",
+		"settings.language.german":  "German",
+
+		"error.snooze_from_due_duration": "Only a duration like \"2 hours\" can be counted from the original due time",
+		"error.snooze_from_due_in_past":  "Counted from the original due time, the timer would be due %s, which is in the past",
+		"error.getting_history":          "Error getting the history",
+		"error.no_history":               "There is no history of any of your timers with this ID",
+		"error.invalid_deleted_timer_id": "There is no deleted timer with this ID, it may have been removed for good already",
+		"error.restoring_timer":          "Error restoring the timer",
+		"error.invalid_tags":             "Invalid tags: %s",
+
+		"error.saving_template":           "Error saving template",
+		"error.getting_templates":         "Error getting templates",
+		"error.unknown_template":          "There is no template called \"%s\"",
+		"error.invalid_template_name":     "Invalid template name: %s",
+		"error.message_and_time_required": "Give a message and a time, or pick a template",
+
+		"embed.restored":             "Timer Restored",
+		"button.undo":                "Undo",
+		"clear_expired.none":         "You have no expired timers.",
+		"clear_expired.done":         "Deleted %d expired timers. They can be brought back with /timer restore for %s.",
+		"error.invalid_duration":     "\"%s\" is not a duration like 1h or 30m",
+		"error.invalid_channel":      "\"%s\" is not a channel of this server you can send messages to",
+		"error.posting_board":        "Could not post the board to this channel",
+		"error.saving_board":         "Error saving the board",
+		"bulk.none":                  "None of your timers match.",
+		"bulk.no_filter":             "Give a query, a tag or expired to select the timers to delete",
+		"bulk.affected":              "Affected timers",
+		"bulk.confirm.delete":        "Delete %d timers?",
+		"bulk.confirm.snooze":        "Snooze %d timers by %s?",
+		"bulk.confirm.shift_later":   "Move %d timers %s later?",
+		"bulk.confirm.shift_earlier": "Move %d timers %s earlier?",
+		"bulk.button.confirm":        "Confirm",
+		"bulk.button.cancel":         "Cancel",
+		"bulk.cancelled":             "Cancelled, nothing was changed.",
+		"bulk.expired":               "This confirmation has expired, please run the command again",
+		"bulk.not_yours":             "Only the person who ran the command can confirm it",
+		"bulk.done.delete":           "Deleted %d timers.",
+		"bulk.done.snooze":           "Snoozed %d timers.",
+		"bulk.done.shift":            "Moved %d timers.",
+		"bulk.restorable":            "They can be brought back with /timer restore for %s.",
+		"bulk.skipped":               "Skipped %d timers that were deleted, shown or snoozed too often in the meantime.",
+		"template.saved":             "Saved template `%s`, use it with /timer template use or /timer create template.",
+		"template.deleted":           "Deleted template `%s`.",
+		"template.list.title":        "Templates",
+		"template.list.empty":        "There are no templates yet, save one with /timer template save.",
+		"template.guild":             "(server)",
+		"template.time":              "Time: %s",
+		"template.channel":           "Channel: <#%s>",
+		"template.follow_ups":        "Then: %s",
+		"template.important":         "Important",
+		"modal.title.new":            "New timer",
+		"modal.title.edit":           "Edit timer %s",
+		"modal.field.message":        "Message",
+		"modal.field.time":           "Time",
+		"modal.field.tags":           "Tags (optional)",
+		"modal.field.channel":        "Channel (optional)",
+		"modal.placeholder.time":     "e.g. tomorrow 9am or 2h",
+		"modal.placeholder.tags":     "e.g. work, bills",
+		"modal.placeholder.channel":  "#channel or channel ID, empty for this channel",
+
+		"embed.field.tags":              "Tags",
+		"embed.reping":                  "Timer Not Acknowledged Yet",
+		"embed.acknowledged":            "Timer Acknowledged",
+		"embed.unacknowledged":          "Timer Escalated",
+		"embed.field.important":         "Important",
+		"embed.field.acknowledged":      "Acknowledged",
+		"ack.button":                    "Got it",
+		"ack.not_allowed":               "Only the owner or the backup can acknowledge this timer.",
+		"ack.done":                      "Acknowledged by %s",
+		"ack.reping":                    "%s, this timer has been due for %s and was not acknowledged yet.",
+		"ack.escalated":                 "%s, %s did not acknowledge this timer.",
+		"ack.reping_every":              "re-ping every %s",
+		"ack.escalate_to":               "to %s after %s",
+		"embed.escalated":               "Snooze Limit Reached",
+		"embed.expired":                 "Timer Expired",
+		"embed.field.snoozed":           "Snoozed",
+		"embed.field.snooze_history":    "Snooze history",
+		"snooze.count":                  "%d×",
+		"snooze.history.more":           "… %d earlier",
+		"snooze.history.entry":          "<t:%d:f> → <t:%d:f> by <@%s> (<t:%d:R>)",
+		"snooze.limit.escalated":        "%s, this timer has already been snoozed %d times. Please take care of it now.",
+		"snooze.limit.escalated_to":     "This timer has already been snoozed %d times, %s was asked to take care of it.",
+		"snooze.limit.expired":          "This timer has already been snoozed %d times and has expired.",
+		"history.title":                 "History of Timer %s",
+		"history.entry":                 "<t:%d:f> **%s** by %s",
+		"history.more":                  "… %d earlier events",
+		"history.actor.bot":             "the bot",
+		"history.event.created":         "Created",
+		"history.event.edited":          "Edited",
+		"history.event.snoozed":         "Snoozed",
+		"history.event.delivered":       "Delivered",
+		"history.event.delivery_failed": "Delivery failed",
+		"history.event.expired":         "Expired",
+		"history.event.deleted":         "Deleted",
+		"history.event.restored":        "Restored",
+		"history.event.acknowledged":    "Acknowledged",
+		"history.event.repinged":        "Re-pinged",
+		"history.event.escalated":       "Escalated",
+		"history.event.deferred":        "Deferred for quiet hours",
+		"embed.field.deferred":          "Deferred",
+		"quiet.deferred_from":           "was due <t:%d:f>",
+		"quiet.silent":                  "Delivered silently during your quiet hours",
+		"dnd.started":                   "Do not disturb until <t:%d:F>.",
+		"dnd.deferred":                  "Timers due until then are delivered afterwards.",
+		"dnd.silent":                    "Timers due until then are delivered without a notification.",
+		"dnd.stopped":                   "Do not disturb ended.",
+		"dnd.quiet_hours":               "Your quiet hours still last until <t:%d:t>.",
+		"board.title.channel":           "Upcoming Timers in this Channel",
+		"board.title.guild":             "Upcoming Timers on this Server",
+		"board.empty":                   "There are no upcoming timers.",
+		"board.updated":                 "Updated",
+		"board.created":                 "Posted the board to this channel, it is kept up to date.",
+		"board.removed":                 "Removed the board of this channel.",
+		"board.none":                    "This channel has no board",
+		"history.detail.due":            "↳ due <t:%s:f>",
+		"history.detail.due_changed":    "↳ due <t:%s:f> → <t:%s:f>",
+		"history.detail.message":        "↳ message \"%s\" → \"%s\"",
+		"history.detail.channel":        "↳ in <#%s>",
+		"history.detail.error":          "↳ error: %s",
+		"history.detail.tags":           "↳ tags %s → %s",
+
+		"error.creating_stopwatch":   "Error creating stopwatch",
+		"error.updating_stopwatch":   "Error updating stopwatch",
+		"error.getting_stopwatches":  "Error getting stopwatches",
+		"error.invalid_stopwatch_id": "Invalid stopwatch ID",
+		"error.not_stopwatch_owner":  "You do not own this stopwatch",
+		"error.stopwatch_paused":     "This stopwatch is paused",
+		"error.stopwatch_not_paused": "This stopwatch is not paused",
+		"error.stopwatch_stopped":    "This stopwatch has already been stopped",
+		"stopwatch.embed.started":    "Stopwatch Started",
+		"stopwatch.embed.lap":        "Lap Recorded",
+		"stopwatch.embed.paused":     "Stopwatch Paused",
+		"stopwatch.embed.resumed":    "Stopwatch Resumed",
+		"stopwatch.embed.stopped":    "Stopwatch Stopped",
+		"stopwatch.field.status":     "Status",
+		"stopwatch.field.elapsed":    "Elapsed",
+		"stopwatch.field.started":    "Started",
+		"stopwatch.field.laps":       "Laps",
+		"stopwatch.status.running":   "running",
+		"stopwatch.status.paused":    "paused",
+		"stopwatch.status.stopped":   "stopped",
+		"stopwatch.lap":              "Lap %d: %s (+%s)",
+		"stopwatch.list.empty":       "You have no stopwatches.",
+		"stopwatch.list.title":       "Stopwatches",
+		"stopwatch.list.entry":       "%s - %s (%s)",
+		"error.creating_pomodoro":    "Error starting pomodoro",
+		"error.updating_pomodoro":    "Error updating pomodoro",
+		"error.getting_pomodoros":    "Error getting pomodoro sessions",
+		"error.invalid_pomodoro_id":  "Invalid pomodoro ID",
+		"pomodoro.not_yours":         "Only the person who started this pomodoro can control it",
+		"pomodoro.work_done":         "Work session %d of %d done, take a break of %s",
+		"pomodoro.break_done":        "Break is over, start work session %d of %d",
+		"pomodoro.finished":          "Pomodoro finished, %d work sessions done",
+		"pomodoro.embed.started":     "Pomodoro Started",
+		"pomodoro.embed.pause":       "Pomodoro Paused",
+		"pomodoro.embed.resume":      "Pomodoro Resumed",
+		"pomodoro.embed.skip":        "Phase Skipped",
+		"pomodoro.embed.stop":        "Pomodoro Stopped",
+		"pomodoro.embed.over":        "Pomodoro Over",
+		"pomodoro.embed.status":      "Pomodoro",
+		"pomodoro.button.pause":      "Pause",
+		"pomodoro.button.resume":     "Resume",
+		"pomodoro.button.skip":       "Skip",
+		"pomodoro.button.stop":       "Stop",
+		"pomodoro.field.plan":        "Plan",
+		"pomodoro.field.phase":       "Phase",
+		"pomodoro.field.ends":        "Ends",
+		"pomodoro.field.completed":   "Completed",
+		"pomodoro.plan":              "%d × %s work, %s break, %s long break",
+		"pomodoro.phase.work":        "Work %d/%d",
+		"pomodoro.phase.break":       "Break after %d/%d",
+		"pomodoro.phase.long_break":  "Long break after %d/%d",
+		"pomodoro.state.running":     "running",
+		"pomodoro.state.paused":      "paused",
+		"pomodoro.state.stopped":     "stopped",
+		"pomodoro.state.finished":    "finished",
+		"pomodoro.remaining":         "%s left",
+		"pomodoro.stats.title":       "Your Pomodoros",
+		"pomodoro.stats.finished":    "Finished sessions",
+		"pomodoro.stats.stopped":     "Stopped sessions",
+		"pomodoro.stats.cycles":      "Work cycles",
+		"pomodoro.stats.work_time":   "Work time",
+
+		"settings.max_snoozes":                  "Snooze limit",
+		"settings.snooze_limit_action":          "At the snooze limit",
+		"settings.snooze_limit_action.escalate": "escalate",
+		"settings.snooze_limit_action.expire":   "expire",
+		"settings.timezone":                     "Timezone",
+		"settings.quiet_hours":                  "Quiet hours",
+		"settings.quiet_mode":                   "During quiet hours",
+		"settings.quiet_mode.defer":             "deliver afterwards",
+		"settings.quiet_mode.silent":            "deliver silently",
+	},
+	LanguageGerman: {
+		"error.invalid_date":        "Ungültiges Datumsformat",
+		"error.invalid_timer_id":    "Ungültige Timer-ID",
+		"error.not_owner":           "Dieser Timer gehört dir nicht",
+		"error.pomodoro_timer":      "Dieser Timer gehört zu einem Pomodoro, steuere ihn mit /pomodoro",
+		"error.message_too_long":    "Die Nachricht darf nicht länger als %d Zeichen sein",
+		"error.timer_limit":         "Du kannst nicht mehr als %d aktive Timer haben",
+		"error.creating_timer_id":   "Fehler beim Erzeugen der Timer-ID",
+		"error.creating_timer":      "Fehler beim Erstellen des Timers",
+		"error.getting_timers":      "Fehler beim Laden der Timer",
+		"error.deleting_timer":      "Fehler beim Löschen des Timers",
+		"error.updating_timer":      "Fehler beim Aktualisieren des Timers",
+		"error.snoozing_timer":      "Fehler beim Verschieben des Timers",
+		"error.getting_timer":       "Fehler beim Laden des verschobenen Timers",
+		"error.saving_settings":     "Fehler beim Speichern der Einstellungen",
+		"error.missing_permission":  "Du brauchst die Berechtigung „Server verwalten“, um Servereinstellungen zu ändern",
+		"error.guild_only":          "Das geht nur auf einem Server",
+		"error.time_empty":          "Bitte gib eine Zeit an, z.B. \"in 2 Stunden\" oder \"morgen 9:00\"",
+		"error.time_not_understood": "Ich konnte \"%s\" nicht als Zeit verstehen. Versuche z.B. \"in 2 Stunden\", \"Freitag 10:00\" oder \"2030-12-24 18:00\"",
+		"error.time_in_past":        "\"%s\" liegt in der Vergangenheit (%s)",
+		"error.invalid_follow_ups":  "Ungültige Folge-Timer: %s",
+		"error.follow_up_time":      "Die Zeit \"%s\" eines Folge-Timers muss eine Dauer wie \"15m\" oder \"in 1 Stunde\" sein, sie zählt ab dem vorherigen Schritt",
+		"choice.prompt":             "\"%s\" kann verschiedene Zeiten bedeuten, welche meinst du?",
+		"choice.placeholder":        "Zeit auswählen",
+		"choice.expired":            "Diese Auswahl ist abgelaufen, bitte führe den Befehl erneut aus",
+		"choice.not_yours":          "Nur die Person, die den Befehl ausgeführt hat, kann die Zeit auswählen",
+		"reason.standard_format":    "genaues Datum",
+		"reason.as_written":         "wie eingegeben",
+		"reason.in_prefix":          "gelesen als \"in …\"",
+		"reason.on_prefix":          "gelesen als \"am …\"",
+		"reason.next_prefix":        "gelesen als \"nächsten …\"",
+		"reason.past":               "wie eingegeben, in der Vergangenheit",
+		"reason.date_order":         "Tag und Monat vertauscht",
+		"until.title":               "Zeit bis",
+		"until.description":         "Zeit bis <t:%d:F>: %s",
+		"until.business_days":       "Verbleibende Werktage: %d",
+		"list.no_active_timers":     "Du hast keine aktiven Timer.",
+		"list.no_timers":            "Du hast keine Timer.",
+		"list.active_title":         "Aktive Timer",
+		"list.all_title":            "Alle Timer",
+		"list.entry":                "%s - Fällig: <t:%d:R>",
+		"list.more":                 "… und %d weitere",
+		"list.scope.channel":        "in diesem Kanal",
+		"list.scope.guild":          "auf diesem Server",
+		"list.empty.channel":        "In diesem Kanal gibt es keine Timer.",
+		"list.empty.guild":          "Auf diesem Server gibt es keine Timer.",
+		"list.owner":                "Von <@%s>",
+		"list.private":              "Privat",
+		"search.title":              "Timer mit „%s“",
+		"search.no_results":         "Keiner deiner Timer enthält „%s“.",
+		"embed.created":             "Timer erstellt",
+		"embed.deleted":             "Timer gelöscht",
+		"embed.snoozed":             "Timer verschoben",
+		"embed.edited":              "Timer bearbeitet",
+		"embed.due":                 "Timer fällig",
+		"embed.field.id":            "ID",
+		"embed.field.owner":         "Besitzer",
+		"embed.field.due":           "Fällig",
+		"embed.field.created":       "Erstellt",
+		"embed.field.chain":         "Kette",
+		"embed.field.follow_ups":    "Folge-Timer",
+		"embed.follow_up":           "Folge-Timer gestartet",
+		"chain.position":            "Schritt %d von %d",
+		"chain.step":                "nach %s: %s",
+		"preview.invalid":           "%s → nicht verstanden",
+		"settings.user_title":       "Deine Einstellungen",
+		"settings.guild_title":      "Servereinstellungen",
+		"settings.language":         "Sprache",
+		"settings.date_order":       "Datumsreihenfolge",
+		"settings.date_languages":   "Datumssprachen",
+		"settings.default_time":     "Standarduhrzeit",
+		"settings.default_time.now": "aktuelle Uhrzeit",
+		"settings.named_times":      "Benannte Uhrzeiten",
+		"settings.weekend":          "Wochenende",
+		"settings.weekend.none":     "keines",
+		"settings.holidays":         "Feiertage",
+		"settings.not_set":          "nicht gesetzt",
+		"settings.saved":            "Einstellungen gespeichert",
+		"settings.language.english": "Englisch",
+		"settings.language.german":  "Deutsch",
+
+		"error.snooze_from_due_duration": "Ab der ursprünglichen Fälligkeit kann nur eine Dauer wie „2 Stunden“ gezählt werden",
+		"error.snooze_from_due_in_past":  "Ab der ursprünglichen Fälligkeit gerechnet wäre der Timer %s fällig, das liegt in der Vergangenheit",
+		"error.getting_history":          "Fehler beim Abrufen des Verlaufs",
+		"error.no_history":               "Für diese ID gibt es keinen Verlauf eines deiner Timer",
+		"error.invalid_deleted_timer_id": "Es gibt keinen gelöschten Timer mit dieser ID, vielleicht wurde er bereits endgültig entfernt",
+		"error.restoring_timer":          "Fehler beim Wiederherstellen des Timers",
+		"error.invalid_tags":             "Ungültige Tags: %s",
+
+		"error.saving_template":           "Fehler beim Speichern der Vorlage",
+		"error.getting_templates":         "Fehler beim Laden der Vorlagen",
+		"error.unknown_template":          "Es gibt keine Vorlage namens \"%s\"",
+		"error.invalid_template_name":     "Ungültiger Vorlagenname: %s",
+		"error.message_and_time_required": "Gib eine Nachricht und eine Zeit an oder wähle eine Vorlage",
+
+		"embed.restored":             "Timer wiederhergestellt",
+		"button.undo":                "Rückgängig",
+		"clear_expired.none":         "Du hast keine abgelaufenen Timer.",
+		"clear_expired.done":         "%d abgelaufene Timer gelöscht. Sie können noch %s lang mit /timer wiederherstellen zurückgeholt werden.",
+		"error.invalid_duration":     "\"%s\" ist keine Dauer wie 1h oder 30m",
+		"error.invalid_channel":      "\"%s\" ist kein Kanal dieses Servers, in den du schreiben kannst",
+		"error.posting_board":        "Die Übersicht konnte nicht in diesem Kanal gepostet werden",
+		"error.saving_board":         "Fehler beim Speichern der Übersicht",
+		"bulk.none":                  "Keiner deiner Timer passt dazu.",
+		"bulk.no_filter":             "Gib eine Suche, einen Tag oder expired an, um die zu löschenden Timer auszuwählen",
+		"bulk.affected":              "Betroffene Timer",
+		"bulk.confirm.delete":        "%d Timer löschen?",
+		"bulk.confirm.snooze":        "%d Timer um %s verschieben?",
+		"bulk.confirm.shift_later":   "%d Timer um %s nach hinten verlegen?",
+		"bulk.confirm.shift_earlier": "%d Timer um %s nach vorne verlegen?",
+		"bulk.button.confirm":        "Bestätigen",
+		"bulk.button.cancel":         "Abbrechen",
+		"bulk.cancelled":             "Abgebrochen, es wurde nichts geändert.",
+		"bulk.expired":               "Diese Bestätigung ist abgelaufen, bitte führe den Befehl erneut aus",
+		"bulk.not_yours":             "Nur die Person, die den Befehl ausgeführt hat, kann ihn bestätigen",
+		"bulk.done.delete":           "%d Timer gelöscht.",
+		"bulk.done.snooze":           "%d Timer verschoben.",
+		"bulk.done.shift":            "%d Timer verlegt.",
+		"bulk.restorable":            "Sie können noch %s lang mit /timer wiederherstellen zurückgeholt werden.",
+		"bulk.skipped":               "%d Timer wurden übersprungen, weil sie inzwischen gelöscht, angezeigt oder zu oft verschoben wurden.",
+		"template.saved":             "Vorlage `%s` gespeichert, nutze sie mit /timer template use oder /timer create template.",
+		"template.deleted":           "Vorlage `%s` gelöscht.",
+		"template.list.title":        "Vorlagen",
+		"template.list.empty":        "Es gibt noch keine Vorlagen, speichere eine mit /timer template save.",
+		"template.guild":             "(Server)",
+		"template.time":              "Zeit: %s",
+		"template.channel":           "Kanal: <#%s>",
+		"template.follow_ups":        "Danach: %s",
+		"template.important":         "Wichtig",
+		"modal.title.new":            "Neuer Timer",
+		"modal.title.edit":           "Timer %s bearbeiten",
+		"modal.field.message":        "Nachricht",
+		"modal.field.time":           "Zeit",
+		"modal.field.tags":           "Tags (optional)",
+		"modal.field.channel":        "Kanal (optional)",
+		"modal.placeholder.time":     "z.B. morgen 9 Uhr oder 2h",
+		"modal.placeholder.tags":     "z.B. arbeit, rechnungen",
+		"modal.placeholder.channel":  "#kanal oder Kanal-ID, leer für diesen Kanal",
+
+		"embed.field.tags":              "Tags",
+		"embed.reping":                  "Timer noch nicht bestätigt",
+		"embed.acknowledged":            "Timer bestätigt",
+		"embed.unacknowledged":          "Timer eskaliert",
+		"embed.field.important":         "Wichtig",
+		"embed.field.acknowledged":      "Bestätigt",
+		"ack.button":                    "Gesehen",
+		"ack.not_allowed":               "Nur der Besitzer oder die Vertretung kann diesen Timer bestätigen.",
+		"ack.done":                      "Bestätigt von %s",
+		"ack.reping":                    "%s, dieser Timer ist seit %s fällig und wurde noch nicht bestätigt.",
+		"ack.escalated":                 "%s, %s hat diesen Timer nicht bestätigt.",
+		"ack.reping_every":              "erneut alle %s",
+		"ack.escalate_to":               "an %s nach %s",
+		"embed.escalated":               "Verschiebelimit erreicht",
+		"embed.expired":                 "Timer abgelaufen",
+		"embed.field.snoozed":           "Verschoben",
+		"embed.field.snooze_history":    "Verschiebeverlauf",
+		"snooze.count":                  "%d×",
+		"snooze.history.more":           "… %d frühere",
+		"snooze.history.entry":          "<t:%d:f> → <t:%d:f> von <@%s> (<t:%d:R>)",
+		"snooze.limit.escalated":        "%s, dieser Timer wurde bereits %d-mal verschoben. Bitte kümmere dich jetzt darum.",
+		"snooze.limit.escalated_to":     "Dieser Timer wurde bereits %d-mal verschoben, %s wurde gebeten, sich darum zu kümmern.",
+		"snooze.limit.expired":          "Dieser Timer wurde bereits %d-mal verschoben und ist abgelaufen.",
+		"history.title":                 "Verlauf von Timer %s",
+		"history.entry":                 "<t:%d:f> **%s** von %s",
+		"history.more":                  "… %d ältere Ereignisse",
+		"history.actor.bot":             "dem Bot",
+		"history.event.created":         "Erstellt",
+		"history.event.edited":          "Bearbeitet",
+		"history.event.snoozed":         "Verschoben",
+		"history.event.delivered":       "Zugestellt",
+		"history.event.delivery_failed": "Zustellung fehlgeschlagen",
+		"history.event.expired":         "Abgelaufen",
+		"history.event.deleted":         "Gelöscht",
+		"history.event.restored":        "Wiederhergestellt",
+		"history.event.acknowledged":    "Bestätigt",
+		"history.event.repinged":        "Erneut erinnert",
+		"history.event.escalated":       "Eskaliert",
+		"history.event.deferred":        "Wegen Ruhezeit zurückgestellt",
+		"embed.field.deferred":          "Zurückgestellt",
+		"quiet.deferred_from":           "war <t:%d:f> fällig",
+		"quiet.silent":                  "Während deiner Ruhezeit still zugestellt",
+		"dnd.started":                   "Nicht stören bis <t:%d:F>.",
+		"dnd.deferred":                  "Bis dahin fällige Timer werden danach zugestellt.",
+		"dnd.silent":                    "Bis dahin fällige Timer werden ohne Benachrichtigung zugestellt.",
+		"dnd.stopped":                   "Nicht stören beendet.",
+		"dnd.quiet_hours":               "Deine Ruhezeit dauert noch bis <t:%d:t>.",
+		"board.title.channel":           "Anstehende Timer in diesem Kanal",
+		"board.title.guild":             "Anstehende Timer auf diesem Server",
+		"board.empty":                   "Es stehen keine Timer an.",
+		"board.updated":                 "Aktualisiert",
+		"board.created":                 "Die Übersicht wurde in diesem Kanal gepostet und wird aktuell gehalten.",
+		"board.removed":                 "Die Übersicht dieses Kanals wurde entfernt.",
+		"board.none":                    "Dieser Kanal hat keine Übersicht",
+		"history.detail.due":            "↳ fällig <t:%s:f>",
+		"history.detail.due_changed":    "↳ fällig <t:%s:f> → <t:%s:f>",
+		"history.detail.message":        "↳ Nachricht „%s“ → „%s“",
+		"history.detail.channel":        "↳ in <#%s>",
+		"history.detail.error":          "↳ Fehler: %s",
+		"history.detail.tags":           "↳ Tags %s → %s",
+
+		"error.creating_stopwatch":   "Fehler beim Erstellen der Stoppuhr",
+		"error.updating_stopwatch":   "Fehler beim Aktualisieren der Stoppuhr",
+		"error.getting_stopwatches":  "Fehler beim Laden der Stoppuhren",
+		"error.invalid_stopwatch_id": "Ungültige Stoppuhr-ID",
+		"error.not_stopwatch_owner":  "Diese Stoppuhr gehört dir nicht",
+		"error.stopwatch_paused":     "Diese Stoppuhr ist pausiert",
+		"error.stopwatch_not_paused": "Diese Stoppuhr ist nicht pausiert",
+		"error.stopwatch_stopped":    "Diese Stoppuhr wurde bereits gestoppt",
+		"stopwatch.embed.started":    "Stoppuhr gestartet",
+		"stopwatch.embed.lap":        "Runde erfasst",
+		"stopwatch.embed.paused":     "Stoppuhr pausiert",
+		"stopwatch.embed.resumed":    "Stoppuhr fortgesetzt",
+		"stopwatch.embed.stopped":    "Stoppuhr gestoppt",
+		"stopwatch.field.status":     "Status",
+		"stopwatch.field.elapsed":    "Vergangen",
+		"stopwatch.field.started":    "Gestartet",
+		"stopwatch.field.laps":       "Runden",
+		"stopwatch.status.running":   "läuft",
+		"stopwatch.status.paused":    "pausiert",
+		"stopwatch.status.stopped":   "gestoppt",
+		"stopwatch.lap":              "Runde %d: %s (+%s)",
+		"stopwatch.list.empty":       "Du hast keine Stoppuhren.",
+		"stopwatch.list.title":       "Stoppuhren",
+		"stopwatch.list.entry":       "%s - %s (%s)",
+		"error.creating_pomodoro":    "Fehler beim Starten des Pomodoros",
+		"error.updating_pomodoro":    "Fehler beim Aktualisieren des Pomodoros",
+		"error.getting_pomodoros":    "Fehler beim Laden der Pomodoro-Sitzungen",
+		"error.invalid_pomodoro_id":  "Ungültige Pomodoro-ID",
+		"pomodoro.not_yours":         "Nur die Person, die diesen Pomodoro gestartet hat, kann ihn steuern",
+		"pomodoro.work_done":         "Arbeitsphase %d von %d erledigt, mach %s Pause",
+		"pomodoro.break_done":        "Die Pause ist vorbei, starte Arbeitsphase %d von %d",
+		"pomodoro.finished":          "Pomodoro beendet, %d Arbeitsphasen erledigt",
+		"pomodoro.embed.started":     "Pomodoro gestartet",
+		"pomodoro.embed.pause":       "Pomodoro pausiert",
+		"pomodoro.embed.resume":      "Pomodoro fortgesetzt",
+		"pomodoro.embed.skip":        "Phase übersprungen",
+		"pomodoro.embed.stop":        "Pomodoro gestoppt",
+		"pomodoro.embed.over":        "Pomodoro vorbei",
+		"pomodoro.embed.status":      "Pomodoro",
+		"pomodoro.button.pause":      "Pause",
+		"pomodoro.button.resume":     "Fortsetzen",
+		"pomodoro.button.skip":       "Überspringen",
+		"pomodoro.button.stop":       "Stoppen",
+		"pomodoro.field.plan":        "Plan",
+		"pomodoro.field.phase":       "Phase",
+		"pomodoro.field.ends":        "Endet",
+		"pomodoro.field.completed":   "Erledigt",
+		"pomodoro.plan":              "%d × %s Arbeit, %s Pause, %s lange Pause",
+		"pomodoro.phase.work":        "Arbeit %d/%d",
+		"pomodoro.phase.break":       "Pause nach %d/%d",
+		"pomodoro.phase.long_break":  "Lange Pause nach %d/%d",
+		"pomodoro.state.running":     "läuft",
+		"pomodoro.state.paused":      "pausiert",
+		"pomodoro.state.stopped":     "gestoppt",
+		"pomodoro.state.finished":    "beendet",
+		"pomodoro.remaining":         "noch %s",
+		"pomodoro.stats.title":       "Deine Pomodoros",
+		"pomodoro.stats.finished":    "Beendete Sitzungen",
+		"pomodoro.stats.stopped":     "Gestoppte Sitzungen",
+		"pomodoro.stats.cycles":      "Arbeitsphasen",
+		"pomodoro.stats.work_time":   "Arbeitszeit",
+
+		"settings.max_snoozes":                  "Verschiebelimit",
+		"settings.snooze_limit_action":          "Beim Verschiebelimit",
+		"settings.snooze_limit_action.escalate": "eskalieren",
+		"settings.snooze_limit_action.expire":   "ablaufen lassen",
+		"settings.timezone":                     "Zeitzone",
+		"settings.quiet_hours":                  "Ruhezeit",
+		"settings.quiet_mode":                   "Während der Ruhezeit",
+		"settings.quiet_mode.defer":             "danach zustellen",
+		"settings.quiet_mode.silent":            "still zustellen",
+	},
+}
+
+// tr looks up the message for key in the given language and formats it with args
+func tr(language string, key string, args ...any) string {
+	message, ok := messages[language][key]
+	if !ok {
+		message, ok = messages[LanguageEnglish][key]
+	}
+	if !ok {
+		message = key
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// languageFromLocale maps a Discord locale like "en-US" or "de" to a supported language
+func languageFromLocale(locale discordgo.Locale) (string, bool) {
+	language, _, _ := strings.Cut(string(locale), "-")
+	for _, supported := range supportedLanguages {
+		if language == supported {
+			return language, true
+		}
+	}
+	return "", false
+}
+
+// dateOrderFromLocale guesses how a user writes numeric dates from their
+// Discord locale, used while no date order is set
+func dateOrderFromLocale(locale discordgo.Locale) string {
+	switch locale {
+	case discordgo.EnglishUS:
+		return "MDY"
+	case discordgo.ChineseCN, discordgo.ChineseTW, discordgo.Japanese, discordgo.Korean, discordgo.Hungarian:
+		return "YMD"
+	}
+	return "DMY"
+}
+
+// getInteractionLanguage picks the language for a response, preferring the
+// user's setting, then the user's client locale, then the guild's setting
+// and finally the guild's locale
+func getInteractionLanguage(interaction *discordgo.InteractionCreate) string {
+	user := getUserFromInteraction(interaction)
+	if language := getLanguageSetting(SettingScopeUser, user.ID); language != "" {
+		return language
+	}
+
+	if language, ok := languageFromLocale(interaction.Locale); ok {
+		return language
+	}
+
+	if interaction.GuildID != "" {
+		if language := getLanguageSetting(SettingScopeGuild, interaction.GuildID); language != "" {
+			return language
+		}
+	}
+
+	if interaction.GuildLocale != nil {
+		if language, ok := languageFromLocale(*interaction.GuildLocale); ok {
+			return language
+		}
+	}
+
+	return LanguageEnglish
+}
+
+// getTimerLanguage picks the language for messages about a timer outside of
+// an interaction, e.g. when it is due
+func getTimerLanguage(timer *Timer) string {
+	if language := getLanguageSetting(SettingScopeUser, timer.User); language != "" {
+		return language
+	}
+	if timer.Language != "" {
+		return timer.Language
+	}
+	return LanguageEnglish
+}
+
+func getLanguageSetting(scope string, scopeID string) string {
+	language, ok, err := getSetting(scope, scopeID, "language")
+	if err != nil {
+		slog.Error("Getting language setting", "error", err)
+		return ""
+	}
+	if !ok {
+		return ""
+	}
+	return language
+}
+
+var germanMagnitudes = []humanize.RelTimeMagnitude{
+	{D: time.Second, Format: "jetzt", DivBy: time.Second},
+	{D: 2 * time.Second, Format: "%s 1 Sekunde", DivBy: 1},
+	{D: time.Minute, Format: "%s %d Sekunden", DivBy: time.Second},
+	{D: 2 * time.Minute, Format: "%s 1 Minute", DivBy: 1},
+	{D: time.Hour, Format: "%s %d Minuten", DivBy: time.Minute},
+	{D: 2 * time.Hour, Format: "%s 1 Stunde", DivBy: 1},
+	{D: humanize.Day, Format: "%s %d Stunden", DivBy: time.Hour},
+	{D: 2 * humanize.Day, Format: "%s 1 Tag", DivBy: 1},
+	{D: humanize.Week, Format: "%s %d Tagen", DivBy: humanize.Day},
+	{D: 2 * humanize.Week, Format: "%s 1 Woche", DivBy: 1},
+	{D: humanize.Month, Format: "%s %d Wochen", DivBy: humanize.Week},
+	{D: 2 * humanize.Month, Format: "%s 1 Monat", DivBy: 1},
+	{D: humanize.Year, Format: "%s %d Monaten", DivBy: humanize.Month},
+	{D: 18 * humanize.Month, Format: "%s 1 Jahr", DivBy: 1},
+	{D: 2 * humanize.Year, Format: "%s 2 Jahren", DivBy: 1},
+	{D: humanize.LongTime, Format: "%s %d Jahren", DivBy: humanize.Year},
+	{D: math.MaxInt64, Format: "%s langer Zeit", DivBy: 1},
+}
+
+// humanizeTime formats a time relative to now, e.g. "3 hours from now"
+func humanizeTime(t time.Time, language string) string {
+	if language == LanguageGerman {
+		return humanize.CustomRelTime(t, time.Now(), "vor", "in", germanMagnitudes)
+	}
+	return humanize.Time(t)
+}
+
+// humanizeDelay formats a duration without direction, e.g. "15 minutes"
+func humanizeDelay(delay time.Duration, language string) string {
+	now := time.Now()
+	if language == LanguageGerman {
+		return strings.TrimSpace(humanize.CustomRelTime(now, now.Add(delay), "", "", germanMagnitudes))
+	}
+	return strings.TrimSpace(humanize.RelTime(now, now.Add(delay), "", ""))
+}
+
+type commandLocalization struct {
+	name        string
+	description string
+}
+
+// commandLocalizations holds the translated names and descriptions of the
+// slash commands, keyed by language and the path of the command or option
+var commandLocalizations = map[discordgo.Locale]map[string]commandLocalization{
+	discordgo.German: {
+		"until":                         {"bis", "Berechnet die Zeit bis zu einem Datum"},
+		"until date":                    {"datum", "Das Datum, bis zu dem die Zeit berechnet wird"},
+		"timer":                         {"timer", "Timer verwalten"},
+		"timer create":                  {"erstellen", "Einen neuen Timer erstellen"},
+		"timer create message":          {"nachricht", "Die Nachricht, die angezeigt wird, wenn der Timer abläuft"},
+		"timer create time":             {"zeit", "Wann der Timer ablaufen soll"},
+		"timer create then":             {"danach", "Folge-Timer, die nacheinander starten, z.B. 15m: prüfen; 1h: ankündigen"},
+		"timer create max_snoozes":      {"verschiebelimit", "Wie oft der Timer verschoben werden kann, bevor er eskaliert oder abläuft"},
+		"timer create tags":             {"tags", "Tags zum Ordnen des Timers, z.B. arbeit, rechnungen"},
+		"timer create important":        {"wichtig", "Erneut erinnern, bis jemand den Timer bestätigt"},
+		"timer create reping_every":     {"erneut_alle", "Minuten zwischen den Erinnerungen eines wichtigen Timers, standardmäßig 15"},
+		"timer create escalate_to":      {"eskalieren_an", "Vertretung oder Rolle, die erinnert wird, wenn niemand den Timer bestätigt"},
+		"timer create escalate_after":   {"eskalieren_nach", "Minuten nach der Fälligkeit, bis die Vertretung erinnert wird, standardmäßig 60"},
+		"timer create private":          {"privat", "Den Timer ausblenden, wenn andere die Timer des Kanals oder Servers auflisten"},
+		"timer new":                     {"neu", "Einen neuen Timer in einem Formular erstellen"},
+		"timer create template":         {"vorlage", "Eine gespeicherte Vorlage, die anderen Optionen überschreiben sie"},
+		"timer list":                    {"liste", "Alle Timer auflisten"},
+		"timer list show_expired":       {"abgelaufene_zeigen", "Ob abgelaufene Timer angezeigt werden sollen"},
+		"timer list tag":                {"tag", "Nur Timer mit diesem Tag auflisten"},
+		"timer list scope":              {"bereich", "Wessen Timer aufgelistet werden, standardmäßig deine eigenen"},
+		"timer search":                  {"suchen", "Deine Timer nach ihrer Nachricht durchsuchen"},
+		"timer search query":            {"suchbegriff", "Text, den die Nachricht des Timers enthält"},
+		"timer search tag":              {"tag", "Nur Timer mit diesem Tag durchsuchen"},
+		"timer search show_expired":     {"abgelaufene_zeigen", "Ob abgelaufene Timer einbezogen werden sollen"},
+		"timer delete":                  {"löschen", "Einen Timer löschen"},
+		"timer delete id":               {"id", "Die ID des zu löschenden Timers"},
+		"timer edit":                    {"bearbeiten", "Einen Timer bearbeiten, nur mit der ID öffnet sich ein Formular"},
+		"timer edit id":                 {"id", "Die ID des zu bearbeitenden Timers"},
+		"timer edit message":            {"nachricht", "Die neue Nachricht des Timers"},
+		"timer edit time":               {"zeit", "Die neue Zeit des Timers"},
+		"timer edit tags":               {"tags", "Die neuen Tags des Timers, none entfernt alle Tags"},
+		"timer snooze":                  {"verschieben", "Einen Timer verschieben"},
+		"timer snooze id":               {"id", "Die ID des zu verschiebenden Timers"},
+		"timer snooze time":             {"zeit", "Um wie viel Zeit der Timer verschoben werden soll"},
+		"timer snooze relative_to":      {"ausgehend_von", "Ob eine Dauer ab jetzt oder ab der ursprünglichen Fälligkeit zählt"},
+		"timer clear-expired":           {"abgelaufene-leeren", "Alle deine Timer löschen, die bereits angezeigt wurden"},
+		"timer restore":                 {"wiederherstellen", "Einen gelöschten Timer wiederherstellen"},
+		"timer restore id":              {"id", "Die ID des gelöschten Timers"},
+		"timer history":                 {"verlauf", "Alles anzeigen, was mit einem Timer passiert ist"},
+		"timer history id":              {"id", "Die ID des Timers, funktioniert auch für gelöschte Timer"},
+		"timer bulk-delete":             {"mehrere-entfernen", "Alle deine Timer löschen, die zu einer Suche oder einem Tag passen"},
+		"timer bulk-delete query":       {"suche", "Nur Timer, deren Nachricht diesen Text enthält"},
+		"timer bulk-delete tag":         {"tag", "Nur Timer mit diesem Tag"},
+		"timer bulk-delete expired":     {"abgelaufene", "Nur Timer, die bereits angezeigt wurden"},
+		"timer bulk-snooze":             {"mehrere-verschieben", "Alle deine heute fälligen Timer verschieben"},
+		"timer bulk-snooze by":          {"um", "Um wie lange die Timer verschoben werden, z.B. 1h oder 30m"},
+		"timer bulk-snooze tag":         {"tag", "Nur Timer mit diesem Tag"},
+		"timer bulk-shift":              {"mehrere-verlegen", "Alle deine Timer in einem Kanal verlegen"},
+		"timer bulk-shift by":           {"um", "Wie weit die Timer verlegt werden, z.B. 2h oder -1d für früher"},
+		"timer bulk-shift channel":      {"kanal", "Der Kanal der Timer, standardmäßig dieser"},
+		"timer bulk-shift tag":          {"tag", "Nur Timer mit diesem Tag"},
+		"timer template":                {"vorlage", "Wiederverwendbare Timer-Vorlagen verwalten"},
+		"timer template save":           {"speichern", "Eine Vorlage speichern, eine gleichnamige wird ersetzt"},
+		"timer template save name":      {"name", "Der Name der Vorlage, z.B. standup"},
+		"timer template save message":   {"nachricht", "Die Nachricht, die angezeigt wird, wenn der Timer abläuft"},
+		"timer template save time":      {"zeit", "Die Zeit, bei jeder Nutzung neu gelesen, z.B. in 1h oder montag 09:00"},
+		"timer template save channel":   {"kanal", "Wohin die Timer gesendet werden, standardmäßig der Kanal der Nutzung"},
+		"timer template save then":      {"danach", "Folge-Timer, die nacheinander starten, z.B. 15m: prüfen; 1h: ankündigen"},
+		"timer template save tags":      {"tags", "Tags zum Ordnen der Timer, z.B. arbeit, rechnungen"},
+		"timer template save important": {"wichtig", "Erneut erinnern, bis jemand den Timer bestätigt"},
+		"timer template save guild":     {"server", "Die Vorlage mit allen auf diesem Server teilen, benötigt Server verwalten"},
+		"timer template use":            {"nutzen", "Einen Timer aus einer Vorlage erstellen"},
+		"timer template use name":       {"name", "Der Name der Vorlage"},
+		"timer template use time":       {"zeit", "Überschreibt die Zeit der Vorlage"},
+		"timer template use message":    {"nachricht", "Überschreibt die Nachricht der Vorlage"},
+		"timer template list":           {"liste", "Deine Vorlagen und die dieses Servers auflisten"},
+		"timer template delete":         {"löschen", "Eine Vorlage löschen"},
+		"timer template delete name":    {"name", "Der Name der Vorlage"},
+		"timer template delete guild":   {"server", "Die Vorlage dieses Servers löschen, benötigt Server verwalten"},
+		"stopwatch":                     {"stoppuhr", "Messen, wie lange etwas dauert"},
+		"stopwatch start":               {"starten", "Eine neue Stoppuhr starten"},
+		"stopwatch start name":          {"name", "Was gemessen wird"},
+		"stopwatch lap":                 {"runde", "Eine Runde erfassen"},
+		"stopwatch lap id":              {"id", "Die ID der Stoppuhr"},
+		"stopwatch pause":               {"pausieren", "Eine Stoppuhr pausieren"},
+		"stopwatch pause id":            {"id", "Die ID der Stoppuhr"},
+		"stopwatch resume":              {"fortsetzen", "Eine pausierte Stoppuhr fortsetzen"},
+		"stopwatch resume id":           {"id", "Die ID der Stoppuhr"},
+		"stopwatch stop":                {"stoppen", "Eine Stoppuhr endgültig stoppen"},
+		"stopwatch stop id":             {"id", "Die ID der Stoppuhr"},
+		"stopwatch list":                {"liste", "Deine Stoppuhren auflisten"},
+		"stopwatch list show_stopped":   {"gestoppte_zeigen", "Ob gestoppte Stoppuhren angezeigt werden sollen"},
+		"pomodoro":                      {"pomodoro", "Zwischen konzentrierter Arbeit und Pausen wechseln"},
+		"pomodoro start":                {"starten", "Eine Pomodoro-Sitzung starten"},
+		"pomodoro start work":           {"arbeit", "Minuten Arbeit pro Phase, standardmäßig 25"},
+		"pomodoro start break":          {"pause", "Minuten Pause zwischen den Phasen, standardmäßig 5"},
+		"pomodoro start long_break":     {"lange_pause", "Minuten der langen Pause am Ende, standardmäßig 15"},
+		"pomodoro start cycles":         {"phasen", "Anzahl der Arbeitsphasen, standardmäßig 4"},
+		"pomodoro stats":                {"statistik", "Deine erledigten Pomodoro-Sitzungen anzeigen"},
+		"settings":                      {"einstellungen", "Einstellungen verwalten"},
+		"settings user":                 {"benutzer", "Deine persönlichen Einstellungen anzeigen oder ändern"},
+		"settings user language":        {"sprache", "Die Sprache der Antworten des Bots"},
+		"settings user date_order":      {"datumsreihenfolge", "Wie Datumsangaben wie 03/04 gelesen werden"},
+		"settings user date_languages":  {"datumssprachen", "Sprachen für Zeitangaben, z.B. de,en oder auto"},
+		"settings user default_time":    {"standarduhrzeit", "Uhrzeit, wenn nur ein Datum angegeben wird, z.B. 09:00, now oder auto"},
+		"settings user named_times":     {"benannte_uhrzeiten", "Eigene Namen für Uhrzeiten, z.B. mittag=12:30, standup=09:15"},
+		"settings guild":                {"server", "Die Einstellungen dieses Servers anzeigen oder ändern"},
+		"settings guild language":       {"sprache", "Die Standardsprache der Antworten auf diesem Server"},
+		"settings guild date_order":     {"datumsreihenfolge", "Wie Datumsangaben wie 03/04 auf diesem Server gelesen werden"},
+		"settings guild date_languages": {"datumssprachen", "Sprachen für Zeitangaben auf diesem Server, z.B. de,en oder auto"},
+		"settings guild default_time":   {"standarduhrzeit", "Uhrzeit, wenn nur ein Datum angegeben wird, z.B. 09:00 oder now"},
+		"settings guild named_times":    {"benannte_uhrzeiten", "Eigene Namen für Uhrzeiten auf diesem Server, z.B. standup=09:15"},
+		"settings guild weekend":        {"wochenende", "Tage ohne Arbeit auf diesem Server, z.B. sa,so, none oder auto"},
+		"settings guild holidays":       {"feiertage", "Feiertagskalender und eigene freie Tage, z.B. de, 2030-12-24, 12-31 oder none"},
+
+		"settings guild max_snoozes":         {"verschiebelimit", "Wie oft Timer auf diesem Server verschoben werden können, z.B. 3 oder none"},
+		"settings guild snooze_limit_action": {"bei_verschiebelimit", "Was mit einem Timer passiert, der das Verschiebelimit erreicht hat"},
+		"settings user timezone":             {"zeitzone", "Deine Zeitzone für die Ruhezeit, z.B. Europe/Berlin oder auto"},
+		"settings user quiet_hours":          {"ruhezeit", "Wann dich Timer nicht anpingen sollen, z.B. 22:00-07:00 oder none"},
+		"settings user quiet_mode":           {"ruhezeit_modus", "Was mit Timern passiert, die während der Ruhezeit fällig werden"},
+		"settings guild quiet_hours":         {"ruhezeit", "Wann Timer dieses Servers nicht anpingen sollen, z.B. 22:00-07:00 oder none"},
+		"settings guild quiet_mode":          {"ruhezeit_modus", "Was mit Timern dieses Servers passiert, die während der Ruhezeit fällig werden"},
+		"dnd":                                {"ruhemodus", "Timer-Benachrichtigungen eine Weile zurückhalten"},
+		"dnd start":                          {"starten", "Bis zur angegebenen Zeit nicht von Timern angepingt werden"},
+		"dnd start until":                    {"bis", "Wann Timer dich wieder anpingen dürfen, z.B. montag 08:00"},
+		"dnd stop":                           {"beenden", "Nicht stören vorzeitig beenden"},
+		"board":                              {"übersicht", "Eine Nachricht mit den anstehenden Timern in diesem Kanal halten"},
+		"board set":                          {"setzen", "Die Übersicht in diesem Kanal posten und seine bisherige ersetzen"},
+		"board set scope":                    {"bereich", "Welche Timer die Übersicht zeigt, standardmäßig die dieses Kanals"},
+		"board set count":                    {"anzahl", "Wie viele Timer die Übersicht zeigt, standardmäßig 10"},
+		"board remove":                       {"entfernen", "Die Übersicht dieses Kanals entfernen"},
+	},
+}
+
+// localizeCommands fills in NameLocalizations and DescriptionLocalizations
+// from commandLocalizations for all commands and their options
+func localizeCommands(commands []*discordgo.ApplicationCommand) {
+	for _, cmd := range commands {
+		names := make(map[discordgo.Locale]string)
+		descriptions := make(map[discordgo.Locale]string)
+		for locale, localizations := range commandLocalizations {
+			if l, ok := localizations[cmd.Name]; ok {
+				names[locale] = l.name
+				descriptions[locale] = l.description
+			}
+		}
+		cmd.NameLocalizations = &names
+		cmd.DescriptionLocalizations = &descriptions
+
+		localizeCommandOptions(cmd.Name, cmd.Options)
+	}
+}
+
+func localizeCommandOptions(path string, options []*discordgo.ApplicationCommandOption) {
+	for _, opt := range options {
+		optPath := path + " " + opt.Name
+		opt.NameLocalizations = make(map[discordgo.Locale]string)
+		opt.DescriptionLocalizations = make(map[discordgo.Locale]string)
+		for locale, localizations := range commandLocalizations {
+			if l, ok := localizations[optPath]; ok {
+				opt.NameLocalizations[locale] = l.name
+				opt.DescriptionLocalizations[locale] = l.description
+			}
+		}
+
+		localizeCommandOptions(optPath, opt.Options)
+	}
+}
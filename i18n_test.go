@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageCatalogIsComplete(t *testing.T) {
+	for _, language := range supportedLanguages {
+		for key := range messages[LanguageEnglish] {
+			assert.Contains(t, messages[language], key, "language %q is missing message %q", language, key)
+		}
+	}
+}
+
+func TestCommandLocalizationsAreComplete(t *testing.T) {
+	var check func(path string, options []*discordgo.ApplicationCommandOption)
+	check = func(path string, options []*discordgo.ApplicationCommandOption) {
+		for _, opt := range options {
+			optPath := path + " " + opt.Name
+			assert.Contains(t, commandLocalizations[discordgo.German], optPath, "missing German localization")
+			check(optPath, opt.Options)
+		}
+	}
+	for _, cmd := range commands {
+		assert.Contains(t, commandLocalizations[discordgo.German], cmd.Name, "missing German localization")
+		check(cmd.Name, cmd.Options)
+	}
+}
+
+func TestDateOrderFromLocale(t *testing.T) {
+	assert.Equal(t, "MDY", dateOrderFromLocale(discordgo.EnglishUS))
+	assert.Equal(t, "DMY", dateOrderFromLocale(discordgo.EnglishGB))
+	assert.Equal(t, "DMY", dateOrderFromLocale(discordgo.German))
+	assert.Equal(t, "YMD", dateOrderFromLocale(discordgo.Japanese))
+	assert.Equal(t, "DMY", dateOrderFromLocale(""))
+}
+
+func TestTr(t *testing.T) {
+	assert.Equal(t, "Ungültige Timer-ID", tr(LanguageGerman, "error.invalid_timer_id"))
+	assert.Equal(t, "You can not have more than 5 active timers", tr(LanguageEnglish, "error.timer_limit", 5))
+	assert.Equal(t, "Invalid timer ID", tr("fr", "error.invalid_timer_id"), "unknown languages fall back to English")
+	assert.Equal(t, "no.such.key", tr(LanguageEnglish, "no.such.key"))
+}
+
+func TestLanguageFromLocale(t *testing.T) {
+	language, ok := languageFromLocale(discordgo.EnglishUS)
+	assert.True(t, ok)
+	assert.Equal(t, LanguageEnglish, language)
+
+	language, ok = languageFromLocale(discordgo.German)
+	assert.True(t, ok)
+	assert.Equal(t, LanguageGerman, language)
+
+	_, ok = languageFromLocale(discordgo.French)
+	assert.False(t, ok)
+}
+
+func TestHumanizeTime(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, "in 3 Stunden", humanizeTime(now.Add(3*time.Hour+time.Minute), LanguageGerman))
+	assert.Equal(t, "vor 2 Tagen", humanizeTime(now.Add(-49*time.Hour), LanguageGerman))
+	assert.Equal(t, "3 hours from now", humanizeTime(now.Add(3*time.Hour+time.Minute), LanguageEnglish))
+}
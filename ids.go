@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Timer ID schemes. Each produces IDs of a shape no other scheme and none of
+// the legacy 4-letter IDs can have, so switching schemes never collides.
+const (
+	// IDSchemeCode gives 6 letters like "kbxrwm", 7 once those run out
+	IDSchemeCode = "code"
+	// IDSchemeWords gives an adjective and a noun like "brave-otter"
+	IDSchemeWords = "words"
+	// IDSchemeNumber gives sequential numbers shared by all users like "12"
+	IDSchemeNumber = "number"
+)
+
+var idSchemes = []string{IDSchemeCode, IDSchemeWords, IDSchemeNumber}
+
+// codeAlphabet leaves out letters that are easily confused like i, l and o
+const codeAlphabet = "abcdefghjkmnpqrstuvwxyz"
+
+const minCodeLength = 6
+
+// encodeCode maps the n-th ID to a code. Within each length the sequence is
+// shuffled by a multiplication coprime to the number of codes, so consecutive
+// IDs don't look alike.
+func encodeCode(n uint64) string {
+	base := uint64(len(codeAlphabet))
+	length := minCodeLength
+	size := pow(base, length)
+	for n >= size {
+		n -= size
+		length++
+		size *= base
+	}
+
+	shuffled := (mulMod(n, codeMultiplier%size, size) + 12345) % size
+	code := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		code[i] = codeAlphabet[shuffled%base]
+		shuffled /= base
+	}
+	return string(code)
+}
+
+// codeMultiplier is prime and not a factor of any number of codes, it is
+// large so that even neighbouring IDs differ in their first letters
+const codeMultiplier = 2654435761
+
+// mulMod returns a*b mod m without overflowing, a and b must be below m
+func mulMod(a uint64, b uint64, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi, lo, m)
+	return rem
+}
+
+func pow(base uint64, exponent int) uint64 {
+	result := uint64(1)
+	for range exponent {
+		result *= base
+	}
+	return result
+}
+
+var idAdjectives = []string{
+	"amber", "bold", "brave", "brisk", "calm", "clever", "cosy", "crisp",
+	"curly", "daring", "eager", "fancy", "fluffy", "frosty", "gentle", "giant",
+	"glad", "golden", "happy", "hasty", "humble", "icy", "jolly", "keen",
+	"kind", "lively", "lucky", "mellow", "merry", "mighty", "misty", "noble",
+	"odd", "plucky", "polite", "proud", "quick", "quiet", "rapid", "rosy",
+	"rusty", "shiny", "silent", "silver", "sleepy", "sly", "snowy", "spicy",
+	"steady", "stormy", "sunny", "swift", "tidy", "tiny", "vivid", "warm",
+	"wild", "windy", "wise", "witty", "young", "zany", "zesty", "zippy",
+}
+
+var idNouns = []string{
+	"badger", "bear", "beaver", "bison", "camel", "cobra", "crane", "crow",
+	"deer", "dolphin", "eagle", "falcon", "ferret", "finch", "fox", "gecko",
+	"goose", "hawk", "heron", "hippo", "ibis", "koala", "lemur", "lion",
+	"llama", "lynx", "marmot", "mole", "moose", "newt", "otter", "owl",
+	"panda", "parrot", "pelican", "penguin", "puffin", "quail", "rabbit", "raven",
+	"robin", "salmon", "seal", "shark", "sloth", "snail", "sparrow", "squid",
+	"stork", "swan", "tapir", "tiger", "toad", "trout", "turtle", "viper",
+	"walrus", "weasel", "whale", "wolf", "wombat", "wren", "yak", "zebra",
+}
+
+// encodeWords maps the n-th ID to an adjective and a noun. Once all pairs
+// are used a round number is appended, e.g. "brave-otter-2".
+func encodeWords(n uint64) string {
+	pairs := uint64(len(idAdjectives) * len(idNouns))
+	round := n / pairs
+	// pairs is a power of two, any odd multiplier shuffles it
+	index := (n%pairs*2749 + 1021) % pairs
+
+	id := idAdjectives[index/uint64(len(idNouns))] + "-" + idNouns[index%uint64(len(idNouns))]
+	if round > 0 {
+		id += "-" + strconv.FormatUint(round+1, 10)
+	}
+	return id
+}
+
+// encodeTimerID turns the n-th value of the sequence of a scheme into an ID
+func encodeTimerID(scheme string, n uint64) (string, error) {
+	switch scheme {
+	case IDSchemeCode:
+		return encodeCode(n), nil
+	case IDSchemeWords:
+		return encodeWords(n), nil
+	case IDSchemeNumber:
+		return strconv.FormatUint(n+1, 10), nil
+	}
+	return "", fmt.Errorf("unknown timer ID scheme %q", scheme)
+}
+
+// normalizeTimerID accepts IDs the way users type them, e.g. "#12" or "KBXRWM"
+func normalizeTimerID(id string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(id)), "#")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeCodeIsUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for n := range uint64(100000) {
+		code := encodeCode(n)
+		require.Len(t, code, minCodeLength)
+		require.False(t, seen[code], "duplicate code %s for %d", code, n)
+		seen[code] = true
+	}
+
+	assert.NotEqual(t, encodeCode(0)[:3], encodeCode(1)[:3])
+}
+
+func TestEncodeCodeGrowsWhenExhausted(t *testing.T) {
+	size := pow(uint64(len(codeAlphabet)), minCodeLength)
+	assert.Len(t, encodeCode(size-1), minCodeLength)
+	assert.Len(t, encodeCode(size), minCodeLength+1)
+	assert.NotEqual(t, encodeCode(size), encodeCode(size+1))
+}
+
+func TestEncodeWords(t *testing.T) {
+	pairs := uint64(len(idAdjectives) * len(idNouns))
+	seen := make(map[string]bool)
+	for n := range 2 * pairs {
+		id := encodeWords(n)
+		require.False(t, seen[id], "duplicate id %s for %d", id, n)
+		seen[id] = true
+	}
+
+	assert.Regexp(t, `^[a-z]+-[a-z]+$`, encodeWords(0))
+	assert.Regexp(t, `^[a-z]+-[a-z]+-2$`, encodeWords(pairs))
+}
+
+func TestIDSchemesDontOverlap(t *testing.T) {
+	legacy := regexp.MustCompile(`^[a-z]{4}$`)
+	shapes := map[string]*regexp.Regexp{
+		IDSchemeCode:   regexp.MustCompile(`^[a-z]{6,}$`),
+		IDSchemeWords:  regexp.MustCompile(`^[a-z]+-[a-z]+(-\d+)?$`),
+		IDSchemeNumber: regexp.MustCompile(`^\d+$`),
+	}
+	for _, scheme := range idSchemes {
+		for n := range uint64(50) {
+			id, err := encodeTimerID(scheme, n)
+			require.NoError(t, err)
+			assert.False(t, legacy.MatchString(id), id)
+			for other, shape := range shapes {
+				assert.Equal(t, scheme == other, shape.MatchString(id), "%s id %s", scheme, id)
+			}
+		}
+	}
+
+	_, err := encodeTimerID("emoji", 0)
+	assert.Error(t, err)
+}
+
+func TestNormalizeTimerID(t *testing.T) {
+	assert.Equal(t, "12", normalizeTimerID("#12"))
+	assert.Equal(t, "kbxrwm", normalizeTimerID(" KBXRWM "))
+	assert.Equal(t, "brave-otter", normalizeTimerID("Brave-Otter"))
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSharedTimerListEmbed(t *testing.T) {
+	due := time.Unix(1900000000, 0)
+	timers := []*Timer{
+		{ID: "a", Message: "Release notes", User: "1", SnoozedDue: due},
+		{ID: "b", Message: "Standup", User: "2", SnoozedDue: due, Private: true, Tags: []string{"team"}},
+	}
+
+	embed := createSharedTimerListEmbed("Active Timers in this Channel", timers, LanguageEnglish)
+	assert.Equal(t, "Release notes - Due: <t:1900000000:R>\nBy <@1>", embed.Fields[0].Value)
+	assert.Equal(t, "Standup - Due: <t:1900000000:R>\nBy <@2> · Private\n`#team`", embed.Fields[1].Value)
+
+	embed = createTimerListEmbed("Active Timers", timers, LanguageEnglish)
+	assert.Equal(t, "Release notes - Due: <t:1900000000:R>", embed.Fields[0].Value)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,42 +11,69 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
-var (
-	token         = os.Getenv("TOKEN")
-	applicationID = os.Getenv("APPLICATION_ID")
-)
-
 func main() {
-	if token == "" || applicationID == "" {
-		fmt.Println("No token or application ID provided")
+	cfg, args, err := loadConfig("timer-bot", os.Args[1:], os.LookupEnv)
+	if err != nil {
+		slog.Error("Loading configuration", "error", err)
+		os.Exit(2)
+	}
+
+	if len(args) > 0 {
+		err = runCommand(cfg, args)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	err := initDB()
+	err = cfg.validate()
+	if err != nil {
+		fmt.Println("Invalid configuration:", err)
+		os.Exit(2)
+	}
+	err = cfg.apply()
 	if err != nil {
-		fmt.Println("Error initializing database:", err)
+		fmt.Println("Error applying configuration:", err)
+		os.Exit(2)
+	}
+	config = cfg
+
+	runBot()
+}
+
+func runBot() {
+	err := initDB(config.Database.Path)
+	if err != nil {
+		slog.Error("Initializing database", "error", err)
 		return
 	}
 
 	session, err := setupDiscordSession()
 	if err != nil {
-		fmt.Println("Error setting up Discord session:", err)
+		slog.Error("Setting up Discord session", "error", err)
 		return
 	}
 
 	registeredCommands, err := registerCommands(session)
 	if err != nil {
-		fmt.Println("Error registering commands:", err)
+		slog.Error("Registering commands", "error", err)
+	}
+
+	if config.HTTP.Listen != "" {
+		startHTTPServer(config.HTTP.Listen)
 	}
 
-	ticker := time.NewTicker(60 * time.Second)
+	ticker := time.NewTicker(config.Scheduler.Interval)
 	go func() {
 		for range ticker.C {
 			checkDueTimers(session)
+			refreshStaleBoards(session)
 		}
 	}()
+	go runRetention()
 
-	fmt.Println("Bot is now running. Press CTRL-C to exit.")
+	slog.Info("Bot is now running. Press CTRL-C to exit.")
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-sc
@@ -54,13 +82,13 @@ func main() {
 }
 
 func setupDiscordSession() (*discordgo.Session, error) {
-	session, err := discordgo.New("Bot " + token)
+	session, err := discordgo.New("Bot " + config.Token)
 	if err != nil {
 		return nil, err
 	}
 
 	session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
-		fmt.Println("Bot is up!")
+		slog.Info("Bot is up!")
 	})
 	session.AddHandler(interactionCreate)
 
@@ -76,27 +104,29 @@ func setupDiscordSession() (*discordgo.Session, error) {
 }
 
 func registerCommands(session *discordgo.Session) ([]*discordgo.ApplicationCommand, error) {
+	localizeCommands(commands)
+
 	registeredCommands := make([]*discordgo.ApplicationCommand, len(commands))
 	for i, cmd := range commands {
-		registered, err := session.ApplicationCommandCreate(applicationID, "", cmd)
+		registered, err := session.ApplicationCommandCreate(config.ApplicationID, "", cmd)
 		if err != nil {
 			return nil, err
 		}
 		registeredCommands[i] = registered
 	}
 
-	fmt.Printf("Registered %d commands\n", len(registeredCommands))
+	slog.Info("Registered commands", "count", len(registeredCommands))
 
 	return registeredCommands, nil
 }
 
 func unregisterAllCommands(session *discordgo.Session, registeredCommands []*discordgo.ApplicationCommand) {
-	fmt.Println("Unregistering all commands...")
+	slog.Info("Unregistering all commands...")
 	for _, cmd := range registeredCommands {
-		err := session.ApplicationCommandDelete(applicationID, "", cmd.ID)
+		err := session.ApplicationCommandDelete(config.ApplicationID, "", cmd.ID)
 		if err != nil {
-			fmt.Println("Error deleting command:", err)
+			slog.Error("Deleting command", "error", err)
 		}
 	}
-	fmt.Println("Unregistered all commands, shutting down...")
+	slog.Info("Unregistered all commands, shutting down...")
 }
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxModalInputLength is the most Discord allows in a text input
+const maxModalInputLength = 4000
+
+// modalTimeLayout is how the due time is prefilled in the edit form, it is
+// one of the standard formats the time parser accepts
+const modalTimeLayout = "2006-01-02 15:04"
+
+// timerModalValues are the fields of the form of /timer new and /timer edit
+type timerModalValues struct {
+	Message string
+	Time    string
+	Tags    string
+	// Channel is a channel mention or ID, empty for the current channel
+	Channel string
+}
+
+// timerModalValuesOf prefills the edit form with the current timer
+func timerModalValuesOf(timer *Timer) timerModalValues {
+	return timerModalValues{
+		Message: timer.Message,
+		Time:    timer.SnoozedDue.Local().Format(modalTimeLayout),
+		Tags:    strings.Join(timer.Tags, ", "),
+	}
+}
+
+// buildTimerModal builds the form, the channel field is only offered where
+// the timer could be sent to another channel
+func buildTimerModal(customID string, title string, values timerModalValues, withChannel bool, language string) *discordgo.InteractionResponseData {
+	messageLength := maxModalInputLength
+	if config.Limits.MaxMessageLength > 0 && config.Limits.MaxMessageLength < messageLength {
+		messageLength = config.Limits.MaxMessageLength
+	}
+
+	inputs := []discordgo.TextInput{
+		{
+			CustomID:  "message",
+			Label:     tr(language, "modal.field.message"),
+			Style:     discordgo.TextInputParagraph,
+			Value:     values.Message,
+			Required:  true,
+			MaxLength: messageLength,
+		},
+		{
+			CustomID:    "time",
+			Label:       tr(language, "modal.field.time"),
+			Style:       discordgo.TextInputShort,
+			Placeholder: tr(language, "modal.placeholder.time"),
+			Value:       values.Time,
+			Required:    true,
+			MaxLength:   100,
+		},
+		{
+			CustomID:    "tags",
+			Label:       tr(language, "modal.field.tags"),
+			Style:       discordgo.TextInputShort,
+			Placeholder: tr(language, "modal.placeholder.tags"),
+			Value:       values.Tags,
+			MaxLength:   200,
+		},
+	}
+	if withChannel {
+		inputs = append(inputs, discordgo.TextInput{
+			CustomID:    "channel",
+			Label:       tr(language, "modal.field.channel"),
+			Style:       discordgo.TextInputShort,
+			Placeholder: tr(language, "modal.placeholder.channel"),
+			Value:       values.Channel,
+			MaxLength:   100,
+		})
+	}
+
+	// Every text input needs a row of its own
+	rows := make([]discordgo.MessageComponent, len(inputs))
+	for i, input := range inputs {
+		rows[i] = discordgo.ActionsRow{Components: []discordgo.MessageComponent{input}}
+	}
+	return &discordgo.InteractionResponseData{
+		CustomID:   customID,
+		Title:      title,
+		Components: rows,
+	}
+}
+
+// readTimerModal collects the submitted fields of the form
+func readTimerModal(data discordgo.ModalSubmitInteractionData) timerModalValues {
+	var values timerModalValues
+	for _, component := range data.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, rowComponent := range row.Components {
+			input, ok := rowComponent.(*discordgo.TextInput)
+			if !ok {
+				continue
+			}
+			value := strings.TrimSpace(input.Value)
+			switch input.CustomID {
+			case "message":
+				values.Message = value
+			case "time":
+				values.Time = value
+			case "tags":
+				values.Tags = value
+			case "channel":
+				values.Channel = value
+			}
+		}
+	}
+	return values
+}
+
+// parseChannelReference accepts a channel mention like <#123> or a bare ID
+func parseChannelReference(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if inner, ok := strings.CutPrefix(value, "<#"); ok {
+		value, ok = strings.CutSuffix(inner, ">")
+		if !ok {
+			return "", false
+		}
+	}
+	if value == "" {
+		return "", false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	return value, true
+}
+
+func stringOption(name string, value string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionString, Value: value}
+}
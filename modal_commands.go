@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func handleTimerNew(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: buildTimerModal("timer_modal:new", tr(language, "modal.title.new"), timerModalValues{}, interaction.GuildID != "", language),
+	}, "handleTimerNew()")
+}
+
+// handleTimerModal handles the submitted forms, argument is "new" or the
+// timer that was edited like "edit:abcd"
+func handleTimerModal(session *discordgo.Session, interaction *discordgo.InteractionCreate, argument string) {
+	values := readTimerModal(interaction.ModalSubmitData())
+	action, timerID, _ := strings.Cut(argument, ":")
+	switch action {
+	case "new":
+		submitTimerNewModal(session, interaction, values)
+	case "edit":
+		submitTimerEditModal(session, interaction, timerID, values)
+	}
+}
+
+// submitTimerNewModal creates the timer the same way /timer create does
+func submitTimerNewModal(session *discordgo.Session, interaction *discordgo.InteractionCreate, values timerModalValues) {
+	language := getInteractionLanguage(interaction)
+
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		stringOption("message", values.Message),
+		stringOption("time", values.Time),
+	}
+	if values.Tags != "" {
+		options = append(options, stringOption("tags", values.Tags))
+	}
+	if values.Channel != "" {
+		channelID, ok := resolveModalChannel(session, interaction, values.Channel)
+		if !ok {
+			respondWithError(session, interaction.Interaction, tr(language, "error.invalid_channel", values.Channel), "submitTimerNewModal() invalid channel", nil)
+			return
+		}
+		options = append(options, &discordgo.ApplicationCommandInteractionDataOption{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel, Value: channelID})
+	}
+
+	createTimerFromOptions(session, interaction, "", options)
+}
+
+// submitTimerEditModal only changes the fields that differ from how the
+// form was prefilled, so that an untouched time isn't parsed again
+func submitTimerEditModal(session *discordgo.Session, interaction *discordgo.InteractionCreate, timerID string, values timerModalValues) {
+	timer, err := getOwnedTimer(session, interaction, timerID, "submitTimerEditModal()")
+	if err != nil {
+		return
+	}
+
+	current := timerModalValuesOf(timer)
+	var options []*discordgo.ApplicationCommandInteractionDataOption
+	if values.Message != current.Message {
+		options = append(options, stringOption("message", values.Message))
+	}
+	if values.Time != current.Time {
+		options = append(options, stringOption("time", values.Time))
+	}
+	if values.Tags != current.Tags {
+		options = append(options, stringOption("tags", values.Tags))
+	}
+
+	editTimerFromOptions(session, interaction, timer.ID, options)
+}
+
+// resolveModalChannel checks that a channel typed into the form is a channel
+// the timer may be sent to
+func resolveModalChannel(session *discordgo.Session, interaction *discordgo.InteractionCreate, value string) (string, bool) {
+	channelID, ok := parseChannelReference(value)
+	if !ok {
+		return "", false
+	}
+	channel, ok := checkTargetChannel(session, interaction, channelID)
+	if !ok {
+		return "", false
+	}
+	return channel.ID, true
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChannelReference(t *testing.T) {
+	for input, expected := range map[string]string{
+		"<#123456>":  "123456",
+		" 987 ":      "987",
+		"<#42>\n":    "42",
+		"1234567890": "1234567890",
+	} {
+		id, ok := parseChannelReference(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, expected, id, input)
+	}
+
+	for _, input := range []string{"", "<#>", "<#123", "#general", "12a"} {
+		_, ok := parseChannelReference(input)
+		assert.False(t, ok, input)
+	}
+}
+
+func TestReadTimerModal(t *testing.T) {
+	var data discordgo.ModalSubmitInteractionData
+	err := json.Unmarshal([]byte(`{
+		"custom_id": "timer_modal:new",
+		"components": [
+			{"type": 1, "components": [{"type": 4, "custom_id": "message", "value": "Water the plants\nand the garden"}]},
+			{"type": 1, "components": [{"type": 4, "custom_id": "time", "value": " tomorrow 9am "}]},
+			{"type": 1, "components": [{"type": 4, "custom_id": "tags", "value": ""}]},
+			{"type": 1, "components": [{"type": 4, "custom_id": "channel", "value": "<#123>"}]}
+		]
+	}`), &data)
+	require.NoError(t, err)
+
+	assert.Equal(t, timerModalValues{
+		Message: "Water the plants\nand the garden",
+		Time:    "tomorrow 9am",
+		Channel: "<#123>",
+	}, readTimerModal(data))
+}
+
+func TestTimerModalValuesOf(t *testing.T) {
+	due := time.Date(2030, time.May, 4, 18, 30, 0, 0, time.Local)
+	values := timerModalValuesOf(&Timer{Message: "Call back", Due: due.Add(-time.Hour), SnoozedDue: due, Tags: []string{"work", "calls"}})
+	assert.Equal(t, timerModalValues{Message: "Call back", Time: "2030-05-04 18:30", Tags: "work, calls"}, values)
+
+	// The prefilled time parses back to the due time
+	candidates, err := parseTimeCandidates(values.Time, defaultParseOptions())
+	require.NoError(t, err)
+	assert.Equal(t, due, candidates[0].Time)
+
+	tags, err := parseTags(values.Tags)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work", "calls"}, tags)
+}
+
+func TestBuildTimerModal(t *testing.T) {
+	data := buildTimerModal("timer_modal:new", "New timer", timerModalValues{}, false, LanguageEnglish)
+	assert.Equal(t, "timer_modal:new", data.CustomID)
+	assert.Len(t, data.Components, 3)
+
+	data = buildTimerModal("timer_modal:new", "New timer", timerModalValues{}, true, LanguageEnglish)
+	assert.Len(t, data.Components, 4)
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	PomodoroStateRunning  = "running"
+	PomodoroStatePaused   = "paused"
+	PomodoroStateStopped  = "stopped"
+	PomodoroStateFinished = "finished"
+)
+
+const (
+	PomodoroPhaseWork      = "work"
+	PomodoroPhaseBreak     = "break"
+	PomodoroPhaseLongBreak = "long_break"
+)
+
+// Pomodoro is a session of work phases separated by breaks. Each phase is a
+// regular timer, the next one is created when it fires.
+type Pomodoro struct {
+	InternalID      int
+	ID              string
+	User            string
+	Channel         string
+	WorkLength      time.Duration
+	BreakLength     time.Duration
+	LongBreakLength time.Duration
+	Cycles          int
+	// Phase is the index of the current phase in Phases()
+	Phase int
+	State string
+	// TimerID is the timer ending the current phase, empty while paused
+	TimerID  string
+	PhaseDue time.Time
+	// Remaining is the time left in the current phase while paused
+	Remaining       time.Duration
+	CompletedCycles int
+	Started         time.Time
+	Finished        *time.Time
+	Language        string
+}
+
+type PomodoroPhase struct {
+	Kind   string
+	Length time.Duration
+	// Cycle is the number of the work phase, breaks have the number of the
+	// work phase before them
+	Cycle int
+}
+
+// Phases returns the work phases with short breaks between them and the
+// long break at the end, if there is one
+func (p *Pomodoro) Phases() []PomodoroPhase {
+	var phases []PomodoroPhase
+	for cycle := 1; cycle <= p.Cycles; cycle++ {
+		phases = append(phases, PomodoroPhase{Kind: PomodoroPhaseWork, Length: p.WorkLength, Cycle: cycle})
+		if cycle < p.Cycles && p.BreakLength > 0 {
+			phases = append(phases, PomodoroPhase{Kind: PomodoroPhaseBreak, Length: p.BreakLength, Cycle: cycle})
+		}
+	}
+	if p.LongBreakLength > 0 {
+		phases = append(phases, PomodoroPhase{Kind: PomodoroPhaseLongBreak, Length: p.LongBreakLength, Cycle: p.Cycles})
+	}
+	return phases
+}
+
+// phaseEndMessage is the notification sent when a phase is over
+func (p *Pomodoro) phaseEndMessage(index int) string {
+	phases := p.Phases()
+	phase := phases[index]
+
+	if index+1 >= len(phases) {
+		return tr(p.Language, "pomodoro.finished", p.Cycles)
+	}
+
+	next := phases[index+1]
+	switch phase.Kind {
+	case PomodoroPhaseWork:
+		return tr(p.Language, "pomodoro.work_done", phase.Cycle, p.Cycles, humanizeDelay(next.Length, p.Language))
+	default:
+		return tr(p.Language, "pomodoro.break_done", next.Cycle, p.Cycles)
+	}
+}
+
+// pomodoroMutex serializes state changes, which come from buttons as well as
+// from checkDueTimers
+var pomodoroMutex sync.Mutex
+
+// startPomodoroPhase creates the timer ending the current phase after length
+func startPomodoroPhase(pomodoro *Pomodoro, length time.Duration, now time.Time) error {
+	id, err := newTimerID()
+	if err != nil {
+		return err
+	}
+
+	due := now.Add(length)
+	timer := &Timer{
+		ID:          id,
+		Message:     pomodoro.phaseEndMessage(pomodoro.Phase),
+		User:        pomodoro.User,
+		Channel:     pomodoro.Channel,
+		Created:     now,
+		Due:         due,
+		SnoozedDue:  due,
+		Language:    pomodoro.Language,
+		ChainID:     id,
+		ChainLength: 1,
+		PomodoroID:  pomodoro.ID,
+	}
+	err = insertTimer(timer)
+	if err != nil {
+		return err
+	}
+	recordTimerEvent(timer, TimerEventCreated, "", map[string]string{"due": unixDetail(due)})
+
+	pomodoro.State = PomodoroStateRunning
+	pomodoro.TimerID = id
+	pomodoro.PhaseDue = due
+	pomodoro.Remaining = 0
+	return updatePomodoro(pomodoro)
+}
+
+// advancePomodoro moves a session to its next phase, either because the
+// timer of the current phase fired or because the phase was skipped
+func advancePomodoro(pomodoro *Pomodoro, completed bool, now time.Time) error {
+	phases := pomodoro.Phases()
+	if completed && phases[pomodoro.Phase].Kind == PomodoroPhaseWork {
+		pomodoro.CompletedCycles++
+	}
+
+	pomodoro.Phase++
+	if pomodoro.Phase >= len(phases) {
+		pomodoro.State = PomodoroStateFinished
+		pomodoro.TimerID = ""
+		pomodoro.Finished = &now
+		return updatePomodoro(pomodoro)
+	}
+
+	return startPomodoroPhase(pomodoro, phases[pomodoro.Phase].Length, now)
+}
+
+// handlePomodoroTimerFired is called from checkDueTimers once the timer of a
+// phase was shown
+func handlePomodoroTimerFired(timer *Timer, now time.Time) {
+	pomodoroMutex.Lock()
+	defer pomodoroMutex.Unlock()
+
+	pomodoro, err := getPomodoroByID(timer.PomodoroID)
+	if err != nil {
+		slog.Error("Getting pomodoro", "error", err)
+		return
+	}
+	// A skipped or stopped phase leaves its timer behind without a session
+	if pomodoro.TimerID != timer.ID || pomodoro.State != PomodoroStateRunning {
+		return
+	}
+
+	err = advancePomodoro(pomodoro, true, now)
+	if err != nil {
+		slog.Error("Advancing pomodoro", "error", err)
+	}
+}
+
+// deletePomodoroTimer removes the timer of the current phase, the owner of the
+// session is the one who paused, skipped or stopped it
+func deletePomodoroTimer(pomodoro *Pomodoro) error {
+	err := deleteTimer(pomodoro.TimerID)
+	if err != nil {
+		return err
+	}
+	recordTimerEvent(&Timer{ID: pomodoro.TimerID, User: pomodoro.User}, TimerEventDeleted, pomodoro.User, nil)
+	return nil
+}
+
+// pausePomodoro keeps the remaining time of the current phase and removes its timer
+func pausePomodoro(pomodoro *Pomodoro, now time.Time) error {
+	err := deletePomodoroTimer(pomodoro)
+	if err != nil {
+		return err
+	}
+	pomodoro.State = PomodoroStatePaused
+	pomodoro.Remaining = max(0, pomodoro.PhaseDue.Sub(now))
+	pomodoro.TimerID = ""
+	return updatePomodoro(pomodoro)
+}
+
+func resumePomodoro(pomodoro *Pomodoro, now time.Time) error {
+	return startPomodoroPhase(pomodoro, pomodoro.Remaining, now)
+}
+
+// skipPomodoroPhase ends the current phase early without counting it
+func skipPomodoroPhase(pomodoro *Pomodoro, now time.Time) error {
+	if pomodoro.TimerID != "" {
+		err := deletePomodoroTimer(pomodoro)
+		if err != nil {
+			return err
+		}
+	}
+	return advancePomodoro(pomodoro, false, now)
+}
+
+func stopPomodoro(pomodoro *Pomodoro, now time.Time) error {
+	if pomodoro.TimerID != "" {
+		err := deletePomodoroTimer(pomodoro)
+		if err != nil {
+			return err
+		}
+	}
+	pomodoro.State = PomodoroStateStopped
+	pomodoro.TimerID = ""
+	pomodoro.Finished = &now
+	return updatePomodoro(pomodoro)
+}
+
+// pomodoroComponents are the buttons to control a session in its current state
+func pomodoroComponents(pomodoro *Pomodoro, language string) []discordgo.MessageComponent {
+	if pomodoro.State != PomodoroStateRunning && pomodoro.State != PomodoroStatePaused {
+		return []discordgo.MessageComponent{}
+	}
+
+	pauseButton := discordgo.Button{
+		Label:    tr(language, "pomodoro.button.pause"),
+		Style:    discordgo.SecondaryButton,
+		CustomID: "pomodoro:pause:" + pomodoro.ID,
+	}
+	if pomodoro.State == PomodoroStatePaused {
+		pauseButton = discordgo.Button{
+			Label:    tr(language, "pomodoro.button.resume"),
+			Style:    discordgo.SuccessButton,
+			CustomID: "pomodoro:resume:" + pomodoro.ID,
+		}
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				pauseButton,
+				discordgo.Button{
+					Label:    tr(language, "pomodoro.button.skip"),
+					Style:    discordgo.PrimaryButton,
+					CustomID: "pomodoro:skip:" + pomodoro.ID,
+				},
+				discordgo.Button{
+					Label:    tr(language, "pomodoro.button.stop"),
+					Style:    discordgo.DangerButton,
+					CustomID: "pomodoro:stop:" + pomodoro.ID,
+				},
+			},
+		},
+	}
+}
+
+// createPomodoroEmbed shows the state of a session in the style of createTimerEmbed
+func createPomodoroEmbed(pomodoro *Pomodoro, owner *discordgo.User, titleKey string, language string) *discordgo.MessageEmbed {
+	phases := pomodoro.Phases()
+
+	status := tr(language, "pomodoro.state."+pomodoro.State)
+	ends := "-"
+	if pomodoro.Phase < len(phases) {
+		phase := phases[pomodoro.Phase]
+		status = tr(language, "pomodoro.phase."+phase.Kind, phase.Cycle, pomodoro.Cycles)
+		switch pomodoro.State {
+		case PomodoroStateRunning:
+			ends = fmt.Sprintf("<t:%d:R>", pomodoro.PhaseDue.Unix())
+		case PomodoroStatePaused:
+			status += " (" + tr(language, "pomodoro.state.paused") + ")"
+			ends = tr(language, "pomodoro.remaining", formatElapsed(pomodoro.Remaining))
+		default:
+			status = tr(language, "pomodoro.state."+pomodoro.State)
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: tr(language, titleKey),
+		Color: 0xff6347,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   tr(language, "embed.field.id"),
+				Value:  pomodoro.ID,
+				Inline: true,
+			},
+			{
+				Name:   tr(language, "embed.field.owner"),
+				Value:  owner.Mention(),
+				Inline: true,
+			},
+			{
+				Name:   tr(language, "pomodoro.field.plan"),
+				Value:  tr(language, "pomodoro.plan", pomodoro.Cycles, formatElapsed(pomodoro.WorkLength), formatElapsed(pomodoro.BreakLength), formatElapsed(pomodoro.LongBreakLength)),
+				Inline: true,
+			},
+			{
+				Name:   tr(language, "pomodoro.field.phase"),
+				Value:  status,
+				Inline: true,
+			},
+			{
+				Name:   tr(language, "pomodoro.field.ends"),
+				Value:  ends,
+				Inline: true,
+			},
+			{
+				Name:   tr(language, "pomodoro.field.completed"),
+				Value:  fmt.Sprintf("%d/%d", pomodoro.CompletedCycles, pomodoro.Cycles),
+				Inline: true,
+			},
+		},
+	}
+}
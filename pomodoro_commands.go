@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultPomodoroWork      = 25 * time.Minute
+	defaultPomodoroBreak     = 5 * time.Minute
+	defaultPomodoroLongBreak = 15 * time.Minute
+	defaultPomodoroCycles    = 4
+)
+
+func handlePomodoro(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	switch interaction.ApplicationCommandData().Options[0].Name {
+	case "start":
+		handlePomodoroStart(session, interaction)
+	case "stats":
+		handlePomodoroStats(session, interaction)
+	}
+}
+
+func handlePomodoroStart(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+	user := getUserFromInteraction(interaction)
+
+	pomodoro := &Pomodoro{
+		User:            user.ID,
+		Channel:         interaction.ChannelID,
+		WorkLength:      defaultPomodoroWork,
+		BreakLength:     defaultPomodoroBreak,
+		LongBreakLength: defaultPomodoroLongBreak,
+		Cycles:          defaultPomodoroCycles,
+		State:           PomodoroStateRunning,
+		Started:         time.Now(),
+		Language:        language,
+	}
+	for _, opt := range interaction.ApplicationCommandData().Options[0].Options {
+		switch opt.Name {
+		case "work":
+			pomodoro.WorkLength = time.Duration(opt.IntValue()) * time.Minute
+		case "break":
+			pomodoro.BreakLength = time.Duration(opt.IntValue()) * time.Minute
+		case "long_break":
+			pomodoro.LongBreakLength = time.Duration(opt.IntValue()) * time.Minute
+		case "cycles":
+			pomodoro.Cycles = int(opt.IntValue())
+		}
+	}
+
+	id, err := newPomodoroID()
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.creating_pomodoro"), "handlePomodoroStart() error creating pomodoro id", err)
+		return
+	}
+	pomodoro.ID = id
+
+	pomodoroMutex.Lock()
+	err = createPomodoro(pomodoro)
+	if err == nil {
+		err = startPomodoroPhase(pomodoro, pomodoro.WorkLength, pomodoro.Started)
+	}
+	pomodoroMutex.Unlock()
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.creating_pomodoro"), "handlePomodoroStart() error creating pomodoro", err)
+		return
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{createPomodoroEmbed(pomodoro, user, "pomodoro.embed.started", language)},
+			Components: pomodoroComponents(pomodoro, language),
+		},
+	}, "handlePomodoroStart() success case")
+}
+
+func handlePomodoroStats(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+	user := getUserFromInteraction(interaction)
+
+	stats, err := getPomodoroStats(user.ID)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.getting_pomodoros"), "handlePomodoroStats() getting stats", err)
+		return
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Title: tr(language, "pomodoro.stats.title"),
+					Color: 0xff6347,
+					Fields: []*discordgo.MessageEmbedField{
+						{Name: tr(language, "pomodoro.stats.finished"), Value: fmt.Sprint(stats.Finished), Inline: true},
+						{Name: tr(language, "pomodoro.stats.stopped"), Value: fmt.Sprint(stats.Stopped), Inline: true},
+						{Name: tr(language, "pomodoro.stats.cycles"), Value: fmt.Sprint(stats.CompletedCycles), Inline: true},
+						{Name: tr(language, "pomodoro.stats.work_time"), Value: formatElapsed(stats.WorkTime), Inline: true},
+					},
+				},
+			},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}, "handlePomodoroStats() success case")
+}
+
+// handlePomodoroButton handles the pause, resume, skip and stop buttons,
+// argument is the action and the ID of the session like "pause:abcd"
+func handlePomodoroButton(session *discordgo.Session, interaction *discordgo.InteractionCreate, argument string) {
+	language := getInteractionLanguage(interaction)
+	action, pomodoroID, _ := strings.Cut(argument, ":")
+
+	pomodoroMutex.Lock()
+	defer pomodoroMutex.Unlock()
+
+	pomodoro, err := getPomodoroByID(pomodoroID)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.invalid_pomodoro_id"), "handlePomodoroButton() invalid pomodoro id", err)
+		return
+	}
+
+	user := getUserFromInteraction(interaction)
+	if pomodoro.User != user.ID {
+		respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: tr(language, "pomodoro.not_yours"),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		}, "handlePomodoroButton() not yours")
+		return
+	}
+
+	now := time.Now()
+	titleKey := "pomodoro.embed." + action
+	switch {
+	case pomodoro.State != PomodoroStateRunning && pomodoro.State != PomodoroStatePaused:
+		err = nil
+		titleKey = "pomodoro.embed.over"
+	case action == "pause" && pomodoro.State == PomodoroStateRunning:
+		err = pausePomodoro(pomodoro, now)
+	case action == "resume" && pomodoro.State == PomodoroStatePaused:
+		err = resumePomodoro(pomodoro, now)
+	case action == "skip":
+		err = skipPomodoroPhase(pomodoro, now)
+	case action == "stop":
+		err = stopPomodoro(pomodoro, now)
+	default:
+		// An old message with buttons for a previous state
+		titleKey = "pomodoro.embed.status"
+	}
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.updating_pomodoro"), "handlePomodoroButton() error updating pomodoro", err)
+		return
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{createPomodoroEmbed(pomodoro, user, titleKey, language)},
+			Components: pomodoroComponents(pomodoro, language),
+		},
+	}, "handlePomodoroButton() success case")
+}
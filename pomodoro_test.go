@@ -0,0 +1,76 @@
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPomodoroPhases(t *testing.T) {
+	pomodoro := &Pomodoro{
+		WorkLength:      25 * time.Minute,
+		BreakLength:     5 * time.Minute,
+		LongBreakLength: 15 * time.Minute,
+		Cycles:          3,
+		Language:        LanguageEnglish,
+	}
+
+	assert.Equal(t, []PomodoroPhase{
+		{Kind: PomodoroPhaseWork, Length: 25 * time.Minute, Cycle: 1},
+		{Kind: PomodoroPhaseBreak, Length: 5 * time.Minute, Cycle: 1},
+		{Kind: PomodoroPhaseWork, Length: 25 * time.Minute, Cycle: 2},
+		{Kind: PomodoroPhaseBreak, Length: 5 * time.Minute, Cycle: 2},
+		{Kind: PomodoroPhaseWork, Length: 25 * time.Minute, Cycle: 3},
+		{Kind: PomodoroPhaseLongBreak, Length: 15 * time.Minute, Cycle: 3},
+	}, pomodoro.Phases())
+
+	assert.Equal(t, "Work session 1 of 3 done, take a break of 5 minutes", pomodoro.phaseEndMessage(0))
+	assert.Equal(t, "Break is over, start work session 2 of 3", pomodoro.phaseEndMessage(1))
+	assert.Equal(t, "Work session 3 of 3 done, take a break of 15 minutes", pomodoro.phaseEndMessage(4))
+	assert.Equal(t, "Pomodoro finished, 3 work sessions done", pomodoro.phaseEndMessage(5))
+
+	t.Run("without breaks", func(t *testing.T) {
+		pomodoro.BreakLength = 0
+		pomodoro.LongBreakLength = 0
+		phases := pomodoro.Phases()
+
+		assert.Len(t, phases, 3)
+		assert.Equal(t, "Pomodoro finished, 3 work sessions done", pomodoro.phaseEndMessage(2))
+	})
+}
+
+func TestRestoreDeletedPomodoroTimer(t *testing.T) {
+	require.NoError(t, initDB(filepath.Join(t.TempDir(), "pomodoro.db")))
+	now := time.Now()
+	pomodoro := &Pomodoro{
+		ID:          "p1",
+		User:        "u1",
+		Channel:     "c1",
+		WorkLength:  25 * time.Minute,
+		BreakLength: 5 * time.Minute,
+		Cycles:      2,
+		State:       PomodoroStateRunning,
+		Started:     now,
+		Language:    LanguageEnglish,
+	}
+	require.NoError(t, createPomodoro(pomodoro))
+	require.NoError(t, startPomodoroPhase(pomodoro, pomodoro.WorkLength, now))
+
+	// The session waits for the timer of its phase, so it can be restored
+	phaseTimer := pomodoro.TimerID
+	require.NoError(t, deleteTimer(phaseTimer))
+	_, err := getDeletedTimerByID(phaseTimer)
+	assert.NoError(t, err)
+	deleted, err := getDeletedTimersForUser("u1")
+	require.NoError(t, err)
+	assert.Len(t, deleted, 1)
+
+	// Once the session moved on the old timer is not restorable anymore
+	require.NoError(t, skipPomodoroPhase(pomodoro, now))
+	_, err = getDeletedTimerByID(phaseTimer)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
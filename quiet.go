@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	QuietModeDefer  = "defer"
+	QuietModeSilent = "silent"
+)
+
+// QuietHours is a daily window without notifications, as minutes after
+// midnight. End before Start means the window spans midnight.
+type QuietHours struct {
+	Start int
+	End   int
+}
+
+// parseQuietHours parses a window like "22:00-07:00" or "10pm-7am"
+func parseQuietHours(value string) (QuietHours, error) {
+	start, end, ok := strings.Cut(value, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("expected a window like 22:00-07:00, got %q", value)
+	}
+	startHour, startMinute, ok := parseClock(start)
+	if !ok {
+		return QuietHours{}, fmt.Errorf("invalid start time %q", strings.TrimSpace(start))
+	}
+	endHour, endMinute, ok := parseClock(end)
+	if !ok {
+		return QuietHours{}, fmt.Errorf("invalid end time %q", strings.TrimSpace(end))
+	}
+
+	hours := QuietHours{Start: startHour*60 + startMinute, End: endHour*60 + endMinute}
+	if hours.Start == hours.End {
+		return QuietHours{}, fmt.Errorf("quiet hours have to end at a different time than they start")
+	}
+	return hours, nil
+}
+
+// String is the inverse of parseQuietHours
+func (q QuietHours) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", q.Start/60, q.Start%60, q.End/60, q.End%60)
+}
+
+// EndOf returns the end of the window t falls into, if it falls into one
+func (q QuietHours) EndOf(t time.Time, location *time.Location) (time.Time, bool) {
+	t = t.In(location)
+	minute := t.Hour()*60 + t.Minute()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+	end := time.Date(t.Year(), t.Month(), t.Day(), q.End/60, q.End%60, 0, 0, location)
+
+	switch {
+	case q.Start < q.End:
+		return end, minute >= q.Start && minute < q.End
+	case minute >= q.Start:
+		return midnight.AddDate(0, 0, 1).Add(time.Duration(q.End) * time.Minute), true
+	case minute < q.End:
+		return end, true
+	}
+	return time.Time{}, false
+}
+
+// QuietSettings are the notification preferences of a user
+type QuietSettings struct {
+	Hours    *QuietHours
+	Location *time.Location
+	// DNDUntil is the end of a temporary do-not-disturb, zero if not set
+	DNDUntil time.Time
+	Mode     string
+}
+
+// QuietUntil returns when notifications are allowed again if t is inside a
+// do-not-disturb or the quiet hours. A do-not-disturb ending inside the
+// quiet hours lasts until they are over as well.
+func (s QuietSettings) QuietUntil(t time.Time) (time.Time, bool) {
+	until := t
+	if s.DNDUntil.After(until) {
+		until = s.DNDUntil
+	}
+	if s.Hours != nil {
+		if end, ok := s.Hours.EndOf(until, s.Location); ok {
+			until = end
+		}
+	}
+	return until, until.After(t)
+}
+
+// getQuietSettings loads the quiet hours, timezone and do-not-disturb of a
+// user. The quiet hours and mode of the guild, usually the one the timer was
+// created in, apply unless the user set their own. They are read in the
+// timezone of the user.
+func getQuietSettings(userID string, guildID string) QuietSettings {
+	settings := QuietSettings{Location: time.Local, Mode: QuietModeDefer}
+
+	if value := resolveSetting(userID, guildID, "quiet_hours"); value != "" {
+		hours, err := parseQuietHours(value)
+		if err != nil {
+			slog.Error("Parsing stored quiet hours", "error", err)
+		} else {
+			settings.Hours = &hours
+		}
+	}
+	if value := resolveSetting(userID, "", "timezone"); value != "" {
+		location, err := time.LoadLocation(value)
+		if err != nil {
+			slog.Error("Loading stored timezone", "error", err)
+		} else {
+			settings.Location = location
+		}
+	}
+	if value := resolveSetting(userID, guildID, "quiet_mode"); value != "" {
+		settings.Mode = value
+	}
+	if value := resolveSetting(userID, "", "dnd_until"); value != "" {
+		unix, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			slog.Error("Parsing stored dnd_until", "error", err)
+		} else {
+			settings.DNDUntil = time.Unix(unix, 0)
+		}
+	}
+	return settings
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func handleDND(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	switch interaction.ApplicationCommandData().Options[0].Name {
+	case "start":
+		handleDNDStart(session, interaction)
+	case "stop":
+		handleDNDStop(session, interaction)
+	}
+}
+
+func handleDNDStart(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	timeStr := interaction.ApplicationCommandData().Options[0].Options[0].StringValue()
+
+	until, ok := resolveTimeInput(session, interaction, timeStr, &pendingTimeChoice{kind: "dnd"})
+	if !ok {
+		return
+	}
+	completeDNDStart(session, interaction, until, discordgo.InteractionResponseChannelMessageWithSource)
+}
+
+// completeDNDStart stores the end of the do-not-disturb once its time is known
+func completeDNDStart(session *discordgo.Session, interaction *discordgo.InteractionCreate, until time.Time, responseType discordgo.InteractionResponseType) {
+	language := getInteractionLanguage(interaction)
+	user := getUserFromInteraction(interaction)
+
+	err := setSetting(SettingScopeUser, user.ID, "dnd_until", strconv.FormatInt(until.Unix(), 10))
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.saving_settings"), "completeDNDStart() saving dnd_until", err)
+		return
+	}
+
+	content := tr(language, "dnd.started", until.Unix())
+	if getQuietSettings(user.ID, interaction.GuildID).Mode == QuietModeSilent {
+		content += "\n" + tr(language, "dnd.silent")
+	} else {
+		content += "\n" + tr(language, "dnd.deferred")
+	}
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	}, "completeDNDStart() success case")
+}
+
+func handleDNDStop(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+	user := getUserFromInteraction(interaction)
+
+	err := deleteSetting(SettingScopeUser, user.ID, "dnd_until")
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.saving_settings"), "handleDNDStop() deleting dnd_until", err)
+		return
+	}
+
+	content := tr(language, "dnd.stopped")
+	if until, quiet := getQuietSettings(user.ID, interaction.GuildID).QuietUntil(time.Now()); quiet {
+		content += "\n" + tr(language, "dnd.quiet_hours", until.Unix())
+	}
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}, "handleDNDStop() success case")
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	hours, err := parseQuietHours("22:00-07:00")
+	require.NoError(t, err)
+	assert.Equal(t, QuietHours{Start: 22 * 60, End: 7 * 60}, hours)
+	assert.Equal(t, "22:00-07:00", hours.String())
+
+	hours, err = parseQuietHours("10pm - 6:30am")
+	require.NoError(t, err)
+	assert.Equal(t, "22:00-06:30", hours.String())
+
+	for _, invalid := range []string{"22:00", "25:00-07:00", "22:00-late", "08:00-08:00"} {
+		_, err = parseQuietHours(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestQuietHoursEndOf(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	overnight := QuietHours{Start: 22 * 60, End: 7 * 60}
+
+	end, ok := overnight.EndOf(time.Date(2030, time.March, 1, 23, 30, 0, 0, berlin), berlin)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2030, time.March, 2, 7, 0, 0, 0, berlin), end)
+
+	end, ok = overnight.EndOf(time.Date(2030, time.March, 2, 3, 0, 0, 0, berlin), berlin)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2030, time.March, 2, 7, 0, 0, 0, berlin), end)
+
+	_, ok = overnight.EndOf(time.Date(2030, time.March, 2, 7, 0, 0, 0, berlin), berlin)
+	assert.False(t, ok)
+
+	// 21:30 UTC is 22:30 in Berlin
+	end, ok = overnight.EndOf(time.Date(2030, time.March, 1, 21, 30, 0, 0, time.UTC), berlin)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2030, time.March, 2, 7, 0, 0, 0, berlin), end)
+
+	lunch := QuietHours{Start: 12 * 60, End: 13 * 60}
+	end, ok = lunch.EndOf(time.Date(2030, time.March, 1, 12, 15, 0, 0, berlin), berlin)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2030, time.March, 1, 13, 0, 0, 0, berlin), end)
+	_, ok = lunch.EndOf(time.Date(2030, time.March, 1, 11, 59, 0, 0, berlin), berlin)
+	assert.False(t, ok)
+}
+
+func TestQuietUntil(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 18, 0, 0, 0, time.UTC)
+	settings := QuietSettings{Location: time.UTC}
+
+	_, quiet := settings.QuietUntil(now)
+	assert.False(t, quiet)
+
+	settings.DNDUntil = now.Add(2 * time.Hour)
+	until, quiet := settings.QuietUntil(now)
+	assert.True(t, quiet)
+	assert.Equal(t, settings.DNDUntil, until)
+
+	// A do-not-disturb ending during the quiet hours lasts until they end
+	settings.Hours = &QuietHours{Start: 19 * 60, End: 7 * 60}
+	until, quiet = settings.QuietUntil(now)
+	assert.True(t, quiet)
+	assert.Equal(t, time.Date(2030, time.March, 2, 7, 0, 0, 0, time.UTC), until)
+
+	settings.DNDUntil = now.Add(-time.Hour)
+	_, quiet = settings.QuietUntil(now)
+	assert.False(t, quiet)
+}
+
+func TestQuietSettingsOfGuild(t *testing.T) {
+	require.NoError(t, initDB(filepath.Join(t.TempDir(), "quiet.db")))
+	require.NoError(t, setSetting(SettingScopeGuild, "g1", "quiet_hours", "22:00-07:00"))
+	require.NoError(t, setSetting(SettingScopeGuild, "g1", "quiet_mode", QuietModeSilent))
+
+	settings := getQuietSettings("u1", "g1")
+	require.NotNil(t, settings.Hours)
+	assert.Equal(t, "22:00-07:00", settings.Hours.String())
+	assert.Equal(t, QuietModeSilent, settings.Mode)
+
+	// Timers of other guilds or DMs keep the defaults
+	settings = getQuietSettings("u1", "")
+	assert.Nil(t, settings.Hours)
+	assert.Equal(t, QuietModeDefer, settings.Mode)
+
+	// The user's own quiet hours take precedence
+	require.NoError(t, setSetting(SettingScopeUser, "u1", "quiet_hours", "23:00-06:00"))
+	settings = getQuietSettings("u1", "g1")
+	assert.Equal(t, "23:00-06:00", settings.Hours.String())
+}
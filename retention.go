@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// applyRetention removes what is past its retention window: deleted timers
+// once they can no longer be restored, and shown timers according to the
+// configured policies
+func applyRetention(cfg RetentionConfig, now time.Time) (purged int64, removed int64, err error) {
+	purged, err = purgeDeletedTimers(now.Add(-cfg.DeletedTimers))
+	if err != nil {
+		return 0, 0, fmt.Errorf("purging deleted timers: %w", err)
+	}
+
+	removed, err = removeShownTimers(retentionPolicy(cfg, now), now)
+	if err != nil {
+		return purged, 0, fmt.Errorf("removing shown timers: %w", err)
+	}
+
+	err = deleteOrphanedTags()
+	if err != nil {
+		return purged, removed, fmt.Errorf("deleting orphaned tags: %w", err)
+	}
+	return purged, removed, nil
+}
+
+// runRetention is the background job applying the retention policies every
+// retention interval
+func runRetention() {
+	ticker := time.NewTicker(config.Retention.Interval)
+	for range ticker.C {
+		purged, removed, err := applyRetention(config.Retention, time.Now())
+		if err != nil {
+			slog.Error("Applying retention", "error", err)
+			continue
+		}
+		if purged > 0 || removed > 0 {
+			slog.Info("Applied retention", "purgedDeleted", purged, "removedShown", removed)
+		}
+	}
+}
+
+func retentionPolicy(cfg RetentionConfig, now time.Time) RetentionPolicy {
+	policy := RetentionPolicy{
+		KeepPerUser: cfg.KeepPerUser,
+		Archive:     cfg.Archive,
+	}
+	if cfg.ShownTimers > 0 {
+		policy.ShownBefore = now.Add(-cfg.ShownTimers)
+	}
+	return policy
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+	now := time.Date(2030, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, RetentionPolicy{}, retentionPolicy(defaultConfig().Retention, now))
+
+	policy := retentionPolicy(RetentionConfig{ShownTimers: 30 * 24 * time.Hour, KeepPerUser: 50, Archive: true}, now)
+	assert.Equal(t, time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC), policy.ShownBefore)
+	assert.Equal(t, 50, policy.KeepPerUser)
+	assert.True(t, policy.Archive)
+}
+
+func TestRemoveShownTimers(t *testing.T) {
+	require.NoError(t, initDB(filepath.Join(t.TempDir(), "retention.db")))
+	now := time.Now()
+	for i, timer := range []struct{ id, user string }{{"a", "u1"}, {"b", "u1"}, {"c", "u1"}, {"d", "u2"}} {
+		due := now.Add(time.Duration(i-4) * time.Hour)
+		_, err := createTimer(timer.id, "Timer "+timer.id, timer.user, "c1", due, LanguageEnglish, TimerOptions{})
+		require.NoError(t, err)
+		require.NoError(t, snoozeTimer(timer.id, due, timer.user))
+		require.NoError(t, setTimerTags(timer.id, []string{"work"}))
+		require.NoError(t, markTimerAsShown(timer.id))
+		recordTimerEvent(&Timer{ID: timer.id, User: timer.user}, TimerEventCreated, timer.user, nil)
+	}
+
+	// A row left from an earlier run is replaced instead of failing the archive
+	first, err := getTimerByID("a")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO timers_archive (internalId, id, message, archivedAt) VALUES (?, 'a', 'stale', ?)", first.InternalID, now)
+	require.NoError(t, err)
+
+	// Only the oldest timer of u1 is beyond the two most recent ones per user
+	removed, err := removeShownTimers(RetentionPolicy{KeepPerUser: 2, Archive: true}, now)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, removed)
+
+	_, err = getTimerByID("a")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	for _, id := range []string{"b", "c", "d"} {
+		_, err = getTimerByID(id)
+		assert.NoError(t, err, id)
+	}
+
+	var message string
+	require.NoError(t, db.QueryRow("SELECT message FROM timers_archive WHERE id = 'a'").Scan(&message))
+	assert.Equal(t, "Timer a", message)
+
+	// The history survives, snoozes and tags move to the archive
+	events, err := getTimerEvents("a", "u1")
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, map[string]int{"snoozes": 0, "timer_tags": 0, "snoozes_archive": 1, "timer_tags_archive": 1}, countTimerRows(t, "a"))
+	assert.Equal(t, map[string]int{"snoozes": 1, "timer_tags": 1, "snoozes_archive": 0, "timer_tags_archive": 0}, countTimerRows(t, "b"))
+
+	require.NoError(t, deleteTimer("d"))
+	purged, err := purgeDeletedTimers(now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, purged)
+	events, err = getTimerEvents("d", "u2")
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, map[string]int{"snoozes": 0, "timer_tags": 0, "snoozes_archive": 0, "timer_tags_archive": 0}, countTimerRows(t, "d"))
+}
+
+// countTimerRows counts the snoozes and tags of a timer and their archived
+// copies
+func countTimerRows(t *testing.T, timerID string) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	for _, table := range []string{"snoozes", "timer_tags", "snoozes_archive", "timer_tags_archive"} {
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table+" WHERE timerId = ?", timerID).Scan(&count))
+		counts[table] = count
+	}
+	return counts
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// settingDefinition describes a setting that can be changed with /settings.
+// The option name of the subcommand is also the key in the settings table.
+type settingDefinition struct {
+	key      string
+	labelKey string
+	// parse validates the value typed by the user. An empty result resets
+	// the setting so that the next fallback applies again.
+	parse func(value string) (string, error)
+	// format renders a stored value for the settings overview
+	format func(value string, language string) string
+	// guildOnly settings can't be overridden by users
+	guildOnly bool
+	// userOnly settings only apply to the user, e.g. when timers are due
+	userOnly bool
+}
+
+var settingDefinitions = []settingDefinition{
+	{
+		key:      "language",
+		labelKey: "settings.language",
+		parse: func(value string) (string, error) {
+			if value == "auto" {
+				return "", nil
+			}
+			for _, supported := range supportedLanguages {
+				if value == supported {
+					return value, nil
+				}
+			}
+			return "", fmt.Errorf("unsupported language %q", value)
+		},
+		format: func(value string, language string) string {
+			switch value {
+			case LanguageEnglish:
+				return tr(language, "settings.language.english")
+			case LanguageGerman:
+				return tr(language, "settings.language.german")
+			}
+			return value
+		},
+	},
+	{
+		key:      "date_order",
+		labelKey: "settings.date_order",
+		parse: func(value string) (string, error) {
+			value = strings.ToUpper(value)
+			if value == "AUTO" {
+				return "", nil
+			}
+			if _, ok := dateOrders[value]; !ok {
+				return "", fmt.Errorf("unknown date order %q", value)
+			}
+			return value, nil
+		},
+		format: func(value string, language string) string {
+			return value
+		},
+	},
+	{
+		key:      "date_languages",
+		labelKey: "settings.date_languages",
+		parse: func(value string) (string, error) {
+			if strings.EqualFold(strings.TrimSpace(value), "auto") {
+				return "", nil
+			}
+			languages := parseDateLanguages(value)
+			if err := validateDateLanguages(languages); err != nil {
+				return "", err
+			}
+			return strings.Join(languages, ","), nil
+		},
+		format: func(value string, language string) string {
+			return strings.ReplaceAll(value, ",", ", ")
+		},
+	},
+	{
+		key:      "default_time",
+		labelKey: "settings.default_time",
+		parse: func(value string) (string, error) {
+			value = strings.ToLower(strings.TrimSpace(value))
+			if value == "auto" {
+				return "", nil
+			}
+			if _, _, _, err := parseTimeOfDay(value); err != nil {
+				return "", err
+			}
+			return value, nil
+		},
+		format: func(value string, language string) string {
+			if value == "now" {
+				return tr(language, "settings.default_time.now")
+			}
+			return value
+		},
+	},
+	{
+		key:      "named_times",
+		labelKey: "settings.named_times",
+		parse: func(value string) (string, error) {
+			if strings.EqualFold(strings.TrimSpace(value), "auto") {
+				return "", nil
+			}
+			namedTimes, err := parseNamedTimes(value)
+			if err != nil {
+				return "", err
+			}
+			return formatNamedTimes(namedTimes), nil
+		},
+		format: func(value string, language string) string {
+			return strings.ReplaceAll(value, ",", ", ")
+		},
+	},
+	{
+		key:      "timezone",
+		labelKey: "settings.timezone",
+		userOnly: true,
+		parse: func(value string) (string, error) {
+			value = strings.TrimSpace(value)
+			if strings.EqualFold(value, "auto") {
+				return "", nil
+			}
+			location, err := time.LoadLocation(value)
+			if err != nil {
+				return "", fmt.Errorf("unknown timezone %q, expected something like Europe/Berlin", value)
+			}
+			return location.String(), nil
+		},
+		format: func(value string, language string) string {
+			return value
+		},
+	},
+	{
+		key:      "quiet_hours",
+		labelKey: "settings.quiet_hours",
+		parse: func(value string) (string, error) {
+			if strings.EqualFold(strings.TrimSpace(value), "none") {
+				return "", nil
+			}
+			hours, err := parseQuietHours(value)
+			if err != nil {
+				return "", err
+			}
+			return hours.String(), nil
+		},
+		format: func(value string, language string) string {
+			return value
+		},
+	},
+	{
+		key:      "quiet_mode",
+		labelKey: "settings.quiet_mode",
+		parse: func(value string) (string, error) {
+			switch value {
+			case "auto":
+				return "", nil
+			case QuietModeDefer, QuietModeSilent:
+				return value, nil
+			}
+			return "", fmt.Errorf("unknown quiet mode %q", value)
+		},
+		format: func(value string, language string) string {
+			return tr(language, "settings.quiet_mode."+value)
+		},
+	},
+	{
+		key:       "weekend",
+		labelKey:  "settings.weekend",
+		guildOnly: true,
+		parse: func(value string) (string, error) {
+			if strings.EqualFold(strings.TrimSpace(value), "auto") {
+				return "", nil
+			}
+			weekend, err := parseWeekend(value)
+			if err != nil {
+				return "", err
+			}
+			if len(weekend) == 0 {
+				return "none", nil
+			}
+			return formatWeekend(weekend), nil
+		},
+		format: func(value string, language string) string {
+			if value == "none" {
+				return tr(language, "settings.weekend.none")
+			}
+			return strings.ReplaceAll(value, ",", ", ")
+		},
+	},
+	{
+		key:       "holidays",
+		labelKey:  "settings.holidays",
+		guildOnly: true,
+		parse: func(value string) (string, error) {
+			if strings.EqualFold(strings.TrimSpace(value), "none") {
+				return "", nil
+			}
+			countries, custom, err := parseHolidays(value)
+			if err != nil {
+				return "", err
+			}
+			return formatHolidays(countries, custom), nil
+		},
+		format: func(value string, language string) string {
+			return strings.ReplaceAll(value, ",", ", ")
+		},
+	},
+	{
+		key:       "max_snoozes",
+		labelKey:  "settings.max_snoozes",
+		guildOnly: true,
+		parse: func(value string) (string, error) {
+			value = strings.TrimSpace(value)
+			if strings.EqualFold(value, "none") || value == "0" {
+				return "", nil
+			}
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 0 {
+				return "", fmt.Errorf("expected a number like 3 or none, got %q", value)
+			}
+			return strconv.Itoa(limit), nil
+		},
+		format: func(value string, language string) string {
+			return value
+		},
+	},
+	{
+		key:       "snooze_limit_action",
+		labelKey:  "settings.snooze_limit_action",
+		guildOnly: true,
+		parse: func(value string) (string, error) {
+			switch value {
+			case "auto":
+				return "", nil
+			case SnoozeLimitEscalate, SnoozeLimitExpire:
+				return value, nil
+			}
+			return "", fmt.Errorf("unknown snooze limit action %q", value)
+		},
+		format: func(value string, language string) string {
+			return tr(language, "settings.snooze_limit_action."+value)
+		},
+	},
+}
+
+// resolveSetting returns the user's value of a setting, falling back to the
+// guild's value and finally to an empty string
+func resolveSetting(userID string, guildID string, key string) string {
+	value, ok, err := getSetting(SettingScopeUser, userID, key)
+	if err != nil {
+		slog.Error("Getting user setting", "key", key, "error", err)
+	}
+	if ok {
+		return value
+	}
+
+	if guildID == "" {
+		return ""
+	}
+	value, _, err = getSetting(SettingScopeGuild, guildID, key)
+	if err != nil {
+		slog.Error("Getting guild setting", "key", key, "error", err)
+	}
+	return value
+}
+
+// getParseOptions builds the time parser options from the settings of the
+// user and guild of an interaction
+func getParseOptions(interaction *discordgo.InteractionCreate) ParseOptions {
+	userID := getUserFromInteraction(interaction).ID
+	options := defaultParseOptions()
+
+	if dateOrder := resolveSetting(userID, interaction.GuildID, "date_order"); dateOrder != "" {
+		options.DateOrder = dateOrder
+		options.StrictDateOrder = true
+	} else {
+		options.DateOrder = dateOrderFromLocale(interaction.Locale)
+	}
+	if languages := resolveSetting(userID, interaction.GuildID, "date_languages"); languages != "" {
+		options.Languages = parseDateLanguages(languages)
+	}
+	if defaultTime := resolveSetting(userID, interaction.GuildID, "default_time"); defaultTime != "" {
+		options.DefaultTimeOfDay = defaultTime
+	}
+	if namedTimes := resolveSetting(userID, interaction.GuildID, "named_times"); namedTimes != "" {
+		parsed, err := parseNamedTimes(namedTimes)
+		if err != nil {
+			slog.Error("Parsing stored named times", "error", err)
+		}
+		options.NamedTimes = parsed
+	}
+
+	options.Calendar = getBusinessCalendar(interaction.GuildID)
+
+	return options
+}
+
+// getBusinessCalendar builds the working day calendar of a guild, outside of
+// guilds the default calendar applies
+func getBusinessCalendar(guildID string) BusinessCalendar {
+	calendar := defaultBusinessCalendar()
+	if guildID == "" {
+		return calendar
+	}
+
+	weekend, ok, err := getSetting(SettingScopeGuild, guildID, "weekend")
+	if err != nil {
+		slog.Error("Getting weekend setting", "error", err)
+	}
+	if ok {
+		calendar.Weekend, err = parseWeekend(weekend)
+		if err != nil {
+			slog.Error("Parsing stored weekend", "error", err)
+		}
+	}
+
+	holidays, ok, err := getSetting(SettingScopeGuild, guildID, "holidays")
+	if err != nil {
+		slog.Error("Getting holidays setting", "error", err)
+	}
+	if ok {
+		calendar.Countries, calendar.CustomHolidays, err = parseHolidays(holidays)
+		if err != nil {
+			slog.Error("Parsing stored holidays", "error", err)
+		}
+	}
+
+	return calendar
+}
+
+func handleSettings(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	subcommand := interaction.ApplicationCommandData().Options[0]
+	language := getInteractionLanguage(interaction)
+
+	scope := SettingScopeUser
+	scopeID := getUserFromInteraction(interaction).ID
+	title := tr(language, "settings.user_title")
+	if subcommand.Name == "guild" {
+		if interaction.GuildID == "" || interaction.Member == nil {
+			respondWithError(session, interaction.Interaction, tr(language, "error.guild_only"), "handleSettings() not in guild", nil)
+			return
+		}
+		if interaction.Member.Permissions&discordgo.PermissionManageGuild == 0 {
+			respondWithError(session, interaction.Interaction, tr(language, "error.missing_permission"), "handleSettings() missing permission", nil)
+			return
+		}
+		scope = SettingScopeGuild
+		scopeID = interaction.GuildID
+		title = tr(language, "settings.guild_title")
+	}
+
+	for _, opt := range subcommand.Options {
+		definition, ok := findSettingDefinition(opt.Name)
+		if !ok {
+			continue
+		}
+
+		value, err := definition.parse(opt.StringValue())
+		if err != nil {
+			respondWithError(session, interaction.Interaction, fmt.Sprintf("%s: %s", tr(language, definition.labelKey), err), "handleSettings() invalid value", err)
+			return
+		}
+
+		if value == "" {
+			err = deleteSetting(scope, scopeID, definition.key)
+		} else {
+			err = setSetting(scope, scopeID, definition.key, value)
+		}
+		if err != nil {
+			respondWithError(session, interaction.Interaction, tr(language, "error.saving_settings"), "handleSettings() saving setting", err)
+			return
+		}
+	}
+
+	// The language setting might just have changed
+	language = getInteractionLanguage(interaction)
+
+	embed := &discordgo.MessageEmbed{
+		Title: title,
+		Color: 0x3c1984,
+	}
+	if len(subcommand.Options) > 0 {
+		embed.Description = tr(language, "settings.saved")
+	}
+
+	for _, definition := range settingDefinitions {
+		if definition.guildOnly && scope != SettingScopeGuild || definition.userOnly && scope != SettingScopeUser {
+			continue
+		}
+
+		display := tr(language, "settings.not_set")
+		value, ok, err := getSetting(scope, scopeID, definition.key)
+		if err != nil {
+			slog.Error("Getting setting in handleSettings()", "error", err)
+		} else if ok {
+			display = definition.format(value, language)
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr(language, definition.labelKey),
+			Value:  display,
+			Inline: true,
+		})
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}, "handleSettings() success case")
+}
+
+func findSettingDefinition(key string) (settingDefinition, bool) {
+	for _, definition := range settingDefinitions {
+		if definition.key == key {
+			return definition, true
+		}
+	}
+	return settingDefinition{}, false
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SnoozeLimitEscalate = "escalate"
+	SnoozeLimitExpire   = "expire"
+)
+
+// mostCommonDurations counts durations rounded to whole minutes and returns
+// up to limit of them, the most frequent first. Ties go to the duration that
+// appears first, so callers pass the most recent snoozes first.
+func mostCommonDurations(durations []time.Duration, limit int) []time.Duration {
+	counts := make(map[time.Duration]int)
+	var order []time.Duration
+	for _, duration := range durations {
+		duration = duration.Round(time.Minute)
+		if duration < time.Minute {
+			continue
+		}
+		if counts[duration] == 0 {
+			order = append(order, duration)
+		}
+		counts[duration]++
+	}
+
+	slices.SortStableFunc(order, func(a, b time.Duration) int {
+		return cmp.Compare(counts[b], counts[a])
+	})
+	if len(order) > limit {
+		order = order[:limit]
+	}
+	return order
+}
+
+// formatCompactDuration writes a duration the way parseCompactDuration reads
+// it, e.g. 1d2h or 1h30m
+func formatCompactDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	var b strings.Builder
+	for _, unit := range []struct {
+		suffix string
+		length time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+	} {
+		if n := d / unit.length; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, unit.suffix)
+			d -= n * unit.length
+		}
+	}
+	if b.Len() == 0 {
+		return "0m"
+	}
+	return b.String()
+}
+
+// getSnoozeSuggestions returns the user's most common snooze durations as
+// input for /timer snooze
+func getSnoozeSuggestions(userID string) []string {
+	durations, err := getCommonSnoozeDurations(userID, 5)
+	if err != nil {
+		slog.Error("Getting common snooze durations", "error", err)
+		return nil
+	}
+
+	suggestions := make([]string, len(durations))
+	for i, duration := range durations {
+		suggestions[i] = formatCompactDuration(duration)
+	}
+	return suggestions
+}
+
+// getSnoozeLimit returns how often a timer can be snoozed, 0 means no limit.
+// The limit of the timer takes precedence over the one of the guild it was
+// created in, wherever it is snoozed from.
+func getSnoozeLimit(timer *Timer) int {
+	if timer.MaxSnoozes > 0 {
+		return timer.MaxSnoozes
+	}
+	if timer.Guild == "" {
+		return 0
+	}
+
+	value, ok, err := getSetting(SettingScopeGuild, timer.Guild, "max_snoozes")
+	if err != nil {
+		slog.Error("Getting max_snoozes setting", "error", err)
+	}
+	if !ok {
+		return 0
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Error("Parsing stored max_snoozes", "error", err)
+	}
+	return limit
+}
+
+// getSnoozeLimitAction returns what happens to a timer that reached its
+// snooze limit, either SnoozeLimitEscalate or SnoozeLimitExpire, as set in
+// the guild the timer was created in
+func getSnoozeLimitAction(timer *Timer) string {
+	if timer.Guild == "" {
+		return SnoozeLimitEscalate
+	}
+	value, ok, err := getSetting(SettingScopeGuild, timer.Guild, "snooze_limit_action")
+	if err != nil {
+		slog.Error("Getting snooze_limit_action setting", "error", err)
+	}
+	if !ok {
+		return SnoozeLimitEscalate
+	}
+	return value
+}
+
+// describeSnoozes summarizes the latest entries of a snooze history for the
+// timer embed
+func describeSnoozes(snoozes []Snooze, language string) string {
+	const maxShown = 5
+	start := max(0, len(snoozes)-maxShown)
+	lines := make([]string, 0, len(snoozes)-start+1)
+	if start > 0 {
+		lines = append(lines, tr(language, "snooze.history.more", start))
+	}
+	for _, snooze := range snoozes[start:] {
+		lines = append(lines, tr(language, "snooze.history.entry", snooze.From.Unix(), snooze.To.Unix(), snooze.User, snooze.SnoozedAt.Unix()))
+	}
+	return strings.Join(lines, "\n")
+}
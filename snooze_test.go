@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMostCommonDurations(t *testing.T) {
+	durations := []time.Duration{
+		10 * time.Minute,
+		time.Hour,
+		time.Hour + 20*time.Second,
+		10 * time.Minute,
+		20 * time.Second,
+		24 * time.Hour,
+		time.Hour,
+	}
+
+	assert.Equal(t, []time.Duration{time.Hour, 10 * time.Minute, 24 * time.Hour}, mostCommonDurations(durations, 5))
+	assert.Equal(t, []time.Duration{time.Hour}, mostCommonDurations(durations, 1))
+	assert.Empty(t, mostCommonDurations(nil, 5))
+}
+
+func TestFormatCompactDuration(t *testing.T) {
+	for _, duration := range []time.Duration{
+		15 * time.Minute,
+		time.Hour,
+		time.Hour + 30*time.Minute,
+		26 * time.Hour,
+		3*24*time.Hour + 5*time.Minute,
+	} {
+		formatted := formatCompactDuration(duration)
+		parsed, ok := parseCompactDuration(formatted)
+		require.True(t, ok, formatted)
+		assert.Equal(t, duration, parsed, formatted)
+	}
+
+	assert.Equal(t, "1h30m", formatCompactDuration(time.Hour+30*time.Minute))
+	assert.Equal(t, "1d2h", formatCompactDuration(26*time.Hour))
+	assert.Equal(t, "0m", formatCompactDuration(10*time.Second))
+}
+
+func TestDescribeSnoozes(t *testing.T) {
+	start := time.Date(2030, time.January, 1, 10, 0, 0, 0, time.UTC)
+	var snoozes []Snooze
+	for i := range 7 {
+		snoozes = append(snoozes, Snooze{
+			From:      start.Add(time.Duration(i) * time.Hour),
+			To:        start.Add(time.Duration(i+1) * time.Hour),
+			User:      "42",
+			SnoozedAt: start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+
+	lines := strings.Split(describeSnoozes(snoozes, LanguageEnglish), "\n")
+	require.Len(t, lines, 6)
+	assert.Equal(t, tr(LanguageEnglish, "snooze.history.more", 2), lines[0])
+	assert.Contains(t, lines[5], "<@42>")
+	assert.Contains(t, lines[5], fmt.Sprintf("<t:%d:f>", snoozes[6].To.Unix()))
+
+	assert.Len(t, strings.Split(describeSnoozes(snoozes[:2], LanguageEnglish), "\n"), 2)
+}
+
+func TestSnoozeLimitFromDM(t *testing.T) {
+	require.NoError(t, initDB(filepath.Join(t.TempDir(), "snooze.db")))
+	require.NoError(t, setSetting(SettingScopeGuild, "g1", "max_snoozes", "1"))
+	require.NoError(t, setSetting(SettingScopeGuild, "g1", "snooze_limit_action", SnoozeLimitExpire))
+
+	due := time.Now().Add(time.Hour)
+	_, err := createTimer("a", "Call back", "u1", "c1", due, LanguageEnglish, TimerOptions{Guild: "g1"})
+	require.NoError(t, err)
+	_, err = createTimer("b", "Water the plants", "u1", "dm", due, LanguageEnglish, TimerOptions{})
+	require.NoError(t, err)
+
+	// Snoozing from a DM applies the settings of the guild of the timer
+	pending := &pendingBulkOperation{kind: BulkSnooze, userID: "u1", timerIDs: []string{"a", "b"}, by: time.Hour}
+	done, skipped := applyBulkOperation(pending)
+	assert.Equal(t, 2, done)
+	assert.Equal(t, 0, skipped)
+
+	done, skipped = applyBulkOperation(pending)
+	assert.Equal(t, 1, done)
+	assert.Equal(t, 1, skipped)
+
+	timer, err := getTimerByID("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, getSnoozeLimit(timer))
+	assert.Equal(t, SnoozeLimitExpire, getSnoozeLimitAction(timer))
+
+	timer, err = getTimerByID("b")
+	require.NoError(t, err)
+	assert.Equal(t, 2, timer.SnoozeCount)
+	assert.Equal(t, 0, getSnoozeLimit(timer))
+	assert.Equal(t, SnoozeLimitEscalate, getSnoozeLimitAction(timer))
+}
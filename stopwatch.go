@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type Stopwatch struct {
+	InternalID int
+	ID         string
+	Name       string
+	User       string
+	Channel    string
+	Started    time.Time
+	// PausedAt is set while the stopwatch is paused
+	PausedAt *time.Time
+	// PausedDuration is the total time spent in finished pauses
+	PausedDuration time.Duration
+	// Stopped is set once the stopwatch was stopped for good
+	Stopped *time.Time
+	// Laps are the elapsed times at which laps were recorded
+	Laps     []time.Duration
+	Language string
+}
+
+func (s *Stopwatch) IsPaused() bool {
+	return s.PausedAt != nil
+}
+
+func (s *Stopwatch) IsStopped() bool {
+	return s.Stopped != nil
+}
+
+// Elapsed returns the time the stopwatch has been running until now,
+// without any pauses
+func (s *Stopwatch) Elapsed(now time.Time) time.Duration {
+	end := now
+	if s.Stopped != nil {
+		end = *s.Stopped
+	}
+	if s.PausedAt != nil && s.PausedAt.Before(end) {
+		end = *s.PausedAt
+	}
+	return end.Sub(s.Started) - s.PausedDuration
+}
+
+// Pause stops the clock until Resume is called
+func (s *Stopwatch) Pause(now time.Time) {
+	s.PausedAt = &now
+}
+
+func (s *Stopwatch) Resume(now time.Time) {
+	if s.PausedAt == nil {
+		return
+	}
+	s.PausedDuration += now.Sub(*s.PausedAt)
+	s.PausedAt = nil
+}
+
+// Stop ends the stopwatch, a running pause is counted as finished
+func (s *Stopwatch) Stop(now time.Time) {
+	s.Resume(now)
+	s.Stopped = &now
+}
+
+func (s *Stopwatch) Lap(now time.Time) time.Duration {
+	elapsed := s.Elapsed(now)
+	s.Laps = append(s.Laps, elapsed)
+	return elapsed
+}
+
+type StopwatchEmbedType struct {
+	titleKey string
+	color    int
+}
+
+var (
+	StopwatchEmbedTypeStart  = StopwatchEmbedType{"stopwatch.embed.started", 0x00ff00}
+	StopwatchEmbedTypeLap    = StopwatchEmbedType{"stopwatch.embed.lap", 0x00ffff}
+	StopwatchEmbedTypePause  = StopwatchEmbedType{"stopwatch.embed.paused", 0xffff00}
+	StopwatchEmbedTypeResume = StopwatchEmbedType{"stopwatch.embed.resumed", 0x00ff00}
+	StopwatchEmbedTypeStop   = StopwatchEmbedType{"stopwatch.embed.stopped", 0xff0000}
+)
+
+// createStopwatchEmbed renders a stopwatch in the same layout as createTimerEmbed
+func createStopwatchEmbed(stopwatch *Stopwatch, owner *discordgo.User, embedType StopwatchEmbedType, language string, now time.Time) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       tr(language, embedType.titleKey),
+		Description: stopwatch.Name,
+		Color:       embedType.color,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   tr(language, "embed.field.id"),
+				Value:  stopwatch.ID,
+				Inline: true,
+			},
+			{
+				Name:   tr(language, "embed.field.owner"),
+				Value:  owner.Mention(),
+				Inline: true,
+			},
+			{
+				Name:   tr(language, "stopwatch.field.status"),
+				Value:  tr(language, stopwatchStatusKey(stopwatch)),
+				Inline: true,
+			},
+			{
+				Name:   tr(language, "stopwatch.field.elapsed"),
+				Value:  formatElapsed(stopwatch.Elapsed(now)),
+				Inline: true,
+			},
+			{
+				Name:   tr(language, "stopwatch.field.started"),
+				Value:  formatTime(stopwatch.Started, false, language),
+				Inline: true,
+			},
+		},
+	}
+
+	if len(stopwatch.Laps) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  tr(language, "stopwatch.field.laps"),
+			Value: describeLaps(stopwatch.Laps, language),
+		})
+	}
+
+	return embed
+}
+
+func stopwatchStatusKey(stopwatch *Stopwatch) string {
+	switch {
+	case stopwatch.IsStopped():
+		return "stopwatch.status.stopped"
+	case stopwatch.IsPaused():
+		return "stopwatch.status.paused"
+	}
+	return "stopwatch.status.running"
+}
+
+// maxLapsShown keeps the laps field below the embed field limit of 1024 characters
+const maxLapsShown = 20
+
+// describeLaps lists the laps with their split time, the most recent ones if
+// there are too many
+func describeLaps(laps []time.Duration, language string) string {
+	start := max(0, len(laps)-maxLapsShown)
+	lines := make([]string, 0, len(laps)-start)
+	for i := start; i < len(laps); i++ {
+		split := laps[i]
+		if i > 0 {
+			split -= laps[i-1]
+		}
+		lines = append(lines, tr(language, "stopwatch.lap", i+1, formatElapsed(laps[i]), formatElapsed(split)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatElapsed formats a duration like a stopwatch display, e.g. 1:02:03
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	seconds := int(d % time.Minute / time.Second)
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
+
+func encodeLaps(laps []time.Duration) (string, error) {
+	if len(laps) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(laps)
+	return string(encoded), err
+}
+
+func decodeLaps(encoded string) ([]time.Duration, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	var laps []time.Duration
+	err := json.Unmarshal([]byte(encoded), &laps)
+	return laps, err
+}
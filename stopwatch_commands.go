@@ -0,0 +1,248 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func handleStopwatch(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	switch interaction.ApplicationCommandData().Options[0].Name {
+	case "start":
+		handleStopwatchStart(session, interaction)
+	case "lap":
+		handleStopwatchLap(session, interaction)
+	case "pause":
+		handleStopwatchPause(session, interaction)
+	case "resume":
+		handleStopwatchResume(session, interaction)
+	case "stop":
+		handleStopwatchStop(session, interaction)
+	case "list":
+		handleStopwatchList(session, interaction)
+	}
+}
+
+func handleStopwatchAutocomplete(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	commandOptions := interaction.ApplicationCommandData().Options
+	if len(commandOptions) == 0 {
+		return
+	}
+
+	var focusedValue string
+	for _, opt := range commandOptions[0].Options {
+		if opt.Focused {
+			focusedValue = strings.ToLower(opt.StringValue())
+			break
+		}
+	}
+
+	stopwatches, err := getStopwatchesForUser(getUserFromInteraction(interaction).ID, false)
+	if err != nil {
+		slog.Error("Fetching stopwatches for autocomplete", "error", err)
+		return
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, 25)
+	for _, stopwatch := range stopwatches {
+		if focusedValue != "" && !strings.Contains(strings.ToLower(stopwatch.ID), focusedValue) {
+			continue
+		}
+
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  buildTimerAutocompleteLabel(stopwatch.ID, stopwatch.Name),
+			Value: stopwatch.ID,
+		})
+
+		if len(choices) == 25 {
+			break
+		}
+	}
+
+	respondWithAutocompleteChoices(session, interaction.Interaction, choices)
+}
+
+func handleStopwatchStart(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+	user := getUserFromInteraction(interaction)
+
+	name := ""
+	for _, opt := range interaction.ApplicationCommandData().Options[0].Options {
+		if opt.Name == "name" {
+			name = opt.StringValue()
+		}
+	}
+	if len(name) > config.Limits.MaxMessageLength {
+		respondWithError(session, interaction.Interaction, tr(language, "error.message_too_long", config.Limits.MaxMessageLength), "handleStopwatchStart() name too long", nil)
+		return
+	}
+
+	id, err := newStopwatchID()
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.creating_stopwatch"), "handleStopwatchStart() error creating stopwatch id", err)
+		return
+	}
+
+	stopwatch, err := createStopwatch(id, name, user.ID, interaction.ChannelID, language)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.creating_stopwatch"), "handleStopwatchStart() error creating stopwatch", err)
+		return
+	}
+
+	respondWithStopwatch(session, interaction, stopwatch, user, StopwatchEmbedTypeStart, "handleStopwatchStart() success case")
+}
+
+func handleStopwatchLap(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	updateOwnedStopwatch(session, interaction, StopwatchEmbedTypeLap, "handleStopwatchLap()", func(stopwatch *Stopwatch, now time.Time) string {
+		if stopwatch.IsPaused() {
+			return "error.stopwatch_paused"
+		}
+		stopwatch.Lap(now)
+		return ""
+	})
+}
+
+func handleStopwatchPause(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	updateOwnedStopwatch(session, interaction, StopwatchEmbedTypePause, "handleStopwatchPause()", func(stopwatch *Stopwatch, now time.Time) string {
+		if stopwatch.IsPaused() {
+			return "error.stopwatch_paused"
+		}
+		stopwatch.Pause(now)
+		return ""
+	})
+}
+
+func handleStopwatchResume(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	updateOwnedStopwatch(session, interaction, StopwatchEmbedTypeResume, "handleStopwatchResume()", func(stopwatch *Stopwatch, now time.Time) string {
+		if !stopwatch.IsPaused() {
+			return "error.stopwatch_not_paused"
+		}
+		stopwatch.Resume(now)
+		return ""
+	})
+}
+
+func handleStopwatchStop(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	updateOwnedStopwatch(session, interaction, StopwatchEmbedTypeStop, "handleStopwatchStop()", func(stopwatch *Stopwatch, now time.Time) string {
+		stopwatch.Stop(now)
+		return ""
+	})
+}
+
+// updateOwnedStopwatch loads the stopwatch given in the id option, applies
+// change to it and responds with the result. change returns the message key
+// of an error if the stopwatch is in the wrong state.
+func updateOwnedStopwatch(session *discordgo.Session, interaction *discordgo.InteractionCreate, embedType StopwatchEmbedType, context string, change func(stopwatch *Stopwatch, now time.Time) string) {
+	language := getInteractionLanguage(interaction)
+	stopwatchID := interaction.ApplicationCommandData().Options[0].Options[0].StringValue()
+
+	stopwatch, err := getOwnedStopwatch(session, interaction, stopwatchID, context)
+	if err != nil {
+		return
+	}
+	if stopwatch.IsStopped() {
+		respondWithError(session, interaction.Interaction, tr(language, "error.stopwatch_stopped"), context+" already stopped", nil)
+		return
+	}
+
+	if errorKey := change(stopwatch, time.Now()); errorKey != "" {
+		respondWithError(session, interaction.Interaction, tr(language, errorKey), context+" wrong state", nil)
+		return
+	}
+
+	err = updateStopwatch(stopwatch)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.updating_stopwatch"), context+" error updating stopwatch", err)
+		return
+	}
+
+	respondWithStopwatch(session, interaction, stopwatch, getUserFromInteraction(interaction), embedType, context+" success case")
+}
+
+func respondWithStopwatch(session *discordgo.Session, interaction *discordgo.InteractionCreate, stopwatch *Stopwatch, owner *discordgo.User, embedType StopwatchEmbedType, context string) {
+	language := getInteractionLanguage(interaction)
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				createStopwatchEmbed(stopwatch, owner, embedType, language, time.Now()),
+			},
+		},
+	}, context)
+}
+
+func handleStopwatchList(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+
+	showStopped := false
+	for _, opt := range interaction.ApplicationCommandData().Options[0].Options {
+		if opt.Name == "show_stopped" {
+			showStopped = opt.BoolValue()
+		}
+	}
+
+	stopwatches, err := getStopwatchesForUser(getUserFromInteraction(interaction).ID, showStopped)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.getting_stopwatches"), "handleStopwatchList() getting stopwatches", err)
+		return
+	}
+
+	if len(stopwatches) == 0 {
+		respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: tr(language, "stopwatch.list.empty"),
+			},
+		}, "handleStopwatchList() no stopwatches")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: tr(language, "stopwatch.list.title"),
+		Color: 0x3c1984,
+	}
+
+	now := time.Now()
+	for _, stopwatch := range stopwatches {
+		name := stopwatch.Name
+		if name == "" {
+			name = "-"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  stopwatch.ID,
+			Value: tr(language, "stopwatch.list.entry", name, formatElapsed(stopwatch.Elapsed(now)), tr(language, stopwatchStatusKey(stopwatch))),
+		})
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	}, "handleStopwatchList() success case")
+}
+
+// getOwnedStopwatch loads a stopwatch and makes sure it belongs to the user
+// of the interaction. On failure the error has already been reported to the user.
+func getOwnedStopwatch(session *discordgo.Session, interaction *discordgo.InteractionCreate, stopwatchID string, context string) (*Stopwatch, error) {
+	language := getInteractionLanguage(interaction)
+
+	stopwatch, err := getStopwatchByID(stopwatchID)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.invalid_stopwatch_id"), context+" invalid stopwatch id", err)
+		return nil, err
+	}
+
+	if stopwatch.User != getUserFromInteraction(interaction).ID {
+		err = errNotStopwatchOwner
+		respondWithError(session, interaction.Interaction, tr(language, "error.not_stopwatch_owner"), context+" not owner", err)
+		return nil, err
+	}
+
+	return stopwatch, nil
+}
+
+var errNotStopwatchOwner = errors.New("stopwatch is owned by another user")
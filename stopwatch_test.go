@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopwatchElapsed(t *testing.T) {
+	start := time.Date(2030, time.January, 1, 10, 0, 0, 0, time.Local)
+	stopwatch := &Stopwatch{Started: start}
+
+	assert.Equal(t, 5*time.Minute, stopwatch.Elapsed(start.Add(5*time.Minute)))
+
+	stopwatch.Pause(start.Add(10 * time.Minute))
+	assert.True(t, stopwatch.IsPaused())
+	assert.Equal(t, 10*time.Minute, stopwatch.Elapsed(start.Add(30*time.Minute)))
+
+	stopwatch.Resume(start.Add(30 * time.Minute))
+	assert.False(t, stopwatch.IsPaused())
+	assert.Equal(t, 15*time.Minute, stopwatch.Elapsed(start.Add(35*time.Minute)))
+
+	assert.Equal(t, 20*time.Minute, stopwatch.Lap(start.Add(40*time.Minute)))
+
+	stopwatch.Pause(start.Add(45 * time.Minute))
+	stopwatch.Stop(start.Add(50 * time.Minute))
+	assert.True(t, stopwatch.IsStopped())
+	assert.False(t, stopwatch.IsPaused())
+	assert.Equal(t, 25*time.Minute, stopwatch.Elapsed(start.Add(2*time.Hour)))
+}
+
+func TestFormatElapsed(t *testing.T) {
+	assert.Equal(t, "0:00", formatElapsed(0))
+	assert.Equal(t, "1:05", formatElapsed(65*time.Second))
+	assert.Equal(t, "1:02:03", formatElapsed(time.Hour+2*time.Minute+3*time.Second))
+	assert.Equal(t, "26:00:00", formatElapsed(26*time.Hour))
+}
+
+func TestDescribeLaps(t *testing.T) {
+	laps := []time.Duration{time.Minute, 3 * time.Minute}
+	assert.Equal(t, "Lap 1: 1:00 (+1:00)\nLap 2: 3:00 (+2:00)", describeLaps(laps, LanguageEnglish))
+
+	encoded, err := encodeLaps(laps)
+	require.NoError(t, err)
+	decoded, err := decodeLaps(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, laps, decoded)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	maxTagsPerTimer = 10
+	maxTagLength    = 32
+)
+
+var tagPattern = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+// tagSeparators are the characters tags can be separated with in an option
+const tagSeparators = ", "
+
+// parseTags reads a list of tags like "work, billing" or "#work #billing".
+// Tags are lowercased and duplicates removed. "none" gives no tags at all.
+func parseTags(value string) ([]string, error) {
+	if strings.EqualFold(strings.TrimSpace(value), "none") {
+		return []string{}, nil
+	}
+
+	tags := []string{}
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return strings.ContainsRune(tagSeparators, r) }) {
+		tag = normalizeTag(tag)
+		if tag == "" || slices.Contains(tags, tag) {
+			continue
+		}
+		if len(tag) > maxTagLength || !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %q", tag)
+		}
+		tags = append(tags, tag)
+	}
+	if len(tags) > maxTagsPerTimer {
+		return nil, fmt.Errorf("at most %d tags are allowed, got %d", maxTagsPerTimer, len(tags))
+	}
+	return tags, nil
+}
+
+// normalizeTag accepts a single tag the way users type it, e.g. "#Work"
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+func formatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "`#" + tag + "`"
+	}
+	return strings.Join(formatted, " ")
+}
+
+// suggestTags completes the last tag of a list the user is typing with the
+// tags they already use, keeping the tags typed before it
+func suggestTags(input string, existing []string) []string {
+	head := ""
+	last := input
+	if i := strings.LastIndexAny(input, tagSeparators); i >= 0 {
+		head = input[:i+1]
+		last = input[i+1:]
+	}
+	last = normalizeTag(last)
+	typed, _ := parseTags(head)
+
+	var suggestions []string
+	for _, tag := range existing {
+		if !strings.HasPrefix(tag, last) || slices.Contains(typed, tag) {
+			continue
+		}
+		suggestions = append(suggestions, head+tag)
+	}
+	return suggestions
+}
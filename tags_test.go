@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := parseTags("Work, billing #work  #Q3_report")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work", "billing", "q3_report"}, tags)
+
+	tags, err = parseTags("privat, überweisung")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"privat", "überweisung"}, tags)
+
+	tags, err = parseTags("none")
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	_, err = parseTags("work, b!lling")
+	assert.Error(t, err)
+
+	_, err = parseTags(strings.Repeat("x", maxTagLength+1))
+	assert.Error(t, err)
+
+	_, err = parseTags("a b c d e f g h i j k")
+	assert.Error(t, err)
+}
+
+func TestSuggestTags(t *testing.T) {
+	existing := []string{"work", "billing", "personal", "weekly"}
+
+	assert.Equal(t, []string{"work", "weekly"}, suggestTags("w", existing))
+	assert.Equal(t, []string{"work", "billing", "personal", "weekly"}, suggestTags("", existing))
+	assert.Equal(t, []string{"work, weekly"}, suggestTags("work, #W", existing))
+	assert.Equal(t, []string{"billing personal"}, suggestTags("billing p", existing))
+}
+
+func TestFormatTags(t *testing.T) {
+	assert.Equal(t, "`#work` `#billing`", formatTags([]string{"work", "billing"}))
+}
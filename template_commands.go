@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func handleTimerTemplate(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	subcommand := interaction.ApplicationCommandData().Options[0].Options[0]
+	switch subcommand.Name {
+	case "save":
+		handleTimerTemplateSave(session, interaction, subcommand.Options)
+	case "use":
+		createTimerFromOptions(session, interaction, subcommand.Options[0].StringValue(), subcommand.Options[1:])
+	case "list":
+		handleTimerTemplateList(session, interaction)
+	case "delete":
+		handleTimerTemplateDelete(session, interaction, subcommand.Options)
+	}
+}
+
+// templateScope returns where a template is stored. Guild templates need
+// the Manage Server permission, otherwise the error has already been
+// reported to the user.
+func templateScope(session *discordgo.Session, interaction *discordgo.InteractionCreate, guild bool, context string) (string, string, bool) {
+	language := getInteractionLanguage(interaction)
+	if !guild {
+		return SettingScopeUser, getUserFromInteraction(interaction).ID, true
+	}
+	if interaction.GuildID == "" || interaction.Member == nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.guild_only"), context+" not in guild", nil)
+		return "", "", false
+	}
+	if interaction.Member.Permissions&discordgo.PermissionManageGuild == 0 {
+		respondWithError(session, interaction.Interaction, tr(language, "error.missing_permission"), context+" missing permission", nil)
+		return "", "", false
+	}
+	return SettingScopeGuild, interaction.GuildID, true
+}
+
+func handleTimerTemplateSave(session *discordgo.Session, interaction *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	language := getInteractionLanguage(interaction)
+
+	template := &TimerTemplate{}
+	guild := false
+	for _, opt := range options {
+		switch opt.Name {
+		case "name":
+			template.Name = opt.StringValue()
+		case "message":
+			template.Message = opt.StringValue()
+		case "time":
+			template.Time = strings.TrimSpace(opt.StringValue())
+		case "channel":
+			template.Channel = fmt.Sprint(opt.Value)
+		case "then":
+			template.FollowUps = strings.TrimSpace(opt.StringValue())
+		case "tags":
+			tags, err := parseTags(opt.StringValue())
+			if err != nil {
+				respondWithError(session, interaction.Interaction, tr(language, "error.invalid_tags", err), "handleTimerTemplateSave() invalid tags", err)
+				return
+			}
+			template.Tags = tags
+		case "important":
+			template.Important = opt.BoolValue()
+		case "guild":
+			guild = opt.BoolValue()
+		}
+	}
+
+	name, err := normalizeTemplateName(template.Name)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.invalid_template_name", err), "handleTimerTemplateSave() invalid name", err)
+		return
+	}
+	template.Name = name
+	if len(template.Message) > config.Limits.MaxMessageLength {
+		respondWithError(session, interaction.Interaction, tr(language, "error.message_too_long", config.Limits.MaxMessageLength), "handleTimerTemplateSave() message too long", nil)
+		return
+	}
+
+	// The time is parsed again whenever the template is used, this only
+	// makes sure that it can be
+	parseOptions := getParseOptions(interaction)
+	if _, err := parseTimeCandidates(template.Time, parseOptions); err != nil {
+		respondWithError(session, interaction.Interaction, describeParseError(err, language), "handleTimerTemplateSave() parsing time", err)
+		return
+	}
+	if template.FollowUps != "" {
+		if _, err := parseFollowUps(template.FollowUps, parseOptions, time.Now()); err != nil {
+			respondWithError(session, interaction.Interaction, describeFollowUpError(err, language), "handleTimerTemplateSave() invalid follow-ups", err)
+			return
+		}
+	}
+
+	var ok bool
+	template.Scope, template.ScopeID, ok = templateScope(session, interaction, guild, "handleTimerTemplateSave()")
+	if !ok {
+		return
+	}
+
+	err = saveTemplate(template)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.saving_template"), "handleTimerTemplateSave() saving template", err)
+		return
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: tr(language, "template.saved", template.Name),
+			Embeds:  []*discordgo.MessageEmbed{createTemplateListEmbed([]*TimerTemplate{template}, language)},
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}, "handleTimerTemplateSave() success case")
+}
+
+func handleTimerTemplateList(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+
+	templates, err := getTemplatesForUser(getUserFromInteraction(interaction).ID, interaction.GuildID)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.getting_templates"), "handleTimerTemplateList() getting templates", err)
+		return
+	}
+
+	data := &discordgo.InteractionResponseData{
+		Content: tr(language, "template.list.empty"),
+		Flags:   discordgo.MessageFlagsEphemeral,
+	}
+	if len(templates) > 0 {
+		data.Content = ""
+		data.Embeds = []*discordgo.MessageEmbed{createTemplateListEmbed(templates, language)}
+	}
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	}, "handleTimerTemplateList() success case")
+}
+
+func handleTimerTemplateDelete(session *discordgo.Session, interaction *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	language := getInteractionLanguage(interaction)
+
+	input := ""
+	guild := false
+	for _, opt := range options {
+		switch opt.Name {
+		case "name":
+			input = opt.StringValue()
+		case "guild":
+			guild = opt.BoolValue()
+		}
+	}
+
+	scope, scopeID, ok := templateScope(session, interaction, guild, "handleTimerTemplateDelete()")
+	if !ok {
+		return
+	}
+
+	name, err := normalizeTemplateName(input)
+	deleted := false
+	if err == nil {
+		deleted, err = deleteTemplate(scope, scopeID, name)
+	}
+	if err != nil || !deleted {
+		respondWithError(session, interaction.Interaction, tr(language, "error.unknown_template", input), "handleTimerTemplateDelete() unknown template", err)
+		return
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: tr(language, "template.deleted", name),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}, "handleTimerTemplateDelete() success case")
+}
+
+// handleTemplateAutocomplete suggests the templates the user can use
+func handleTemplateAutocomplete(session *discordgo.Session, interaction *discordgo.InteractionCreate, input string) {
+	templates, err := getTemplatesForUser(getUserFromInteraction(interaction).ID, interaction.GuildID)
+	if err != nil {
+		slog.Error("Fetching templates for autocomplete", "error", err)
+		return
+	}
+
+	input = strings.ToLower(strings.TrimSpace(input))
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, 25)
+	for _, template := range templates {
+		if input != "" && !strings.Contains(template.Name, input) {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  buildTimerAutocompleteLabel(template.Name, template.Message),
+			Value: template.Name,
+		})
+		if len(choices) == 25 {
+			break
+		}
+	}
+
+	respondWithAutocompleteChoices(session, interaction.Interaction, choices)
+}
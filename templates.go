@@ -0,0 +1,126 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const maxTemplateNameLength = 32
+
+// TimerTemplate is a stored shape of a timer. Time and FollowUps are kept as
+// typed, so that relative times like "in 1h" count from when it is used.
+type TimerTemplate struct {
+	// Scope is SettingScopeUser or SettingScopeGuild, guild templates can be
+	// used by everyone in the guild
+	Scope   string
+	ScopeID string
+	Name    string
+	Message string
+	Time    string
+	// Channel is where timers of the template are sent, empty for the
+	// channel the template is used in
+	Channel   string
+	FollowUps string
+	Tags      []string
+	Important bool
+}
+
+// normalizeTemplateName accepts the name the way users type it
+func normalizeTemplateName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || len(name) > maxTemplateNameLength || !tagPattern.MatchString(name) {
+		return "", fmt.Errorf("invalid template name %q, use up to %d letters, digits, - or _", name, maxTemplateNameLength)
+	}
+	return name, nil
+}
+
+// resolveTemplate looks up a template of the user, falling back to the
+// templates of the guild like resolveSetting does
+func resolveTemplate(userID string, guildID string, name string) (*TimerTemplate, error) {
+	template, err := getTemplate(SettingScopeUser, userID, name)
+	if errors.Is(err, sql.ErrNoRows) && guildID != "" {
+		template, err = getTemplate(SettingScopeGuild, guildID, name)
+	}
+	return template, err
+}
+
+// getTemplatesForUser returns the templates of the user followed by the
+// ones of the guild that the user has no template of the same name for
+func getTemplatesForUser(userID string, guildID string) ([]*TimerTemplate, error) {
+	templates, err := getTemplates(SettingScopeUser, userID)
+	if err != nil || guildID == "" {
+		return templates, err
+	}
+
+	guildTemplates, err := getTemplates(SettingScopeGuild, guildID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(templates))
+	for _, template := range templates {
+		names[template.Name] = true
+	}
+	for _, template := range guildTemplates {
+		if !names[template.Name] {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+// describeTemplate summarizes a template for /timer template list
+func describeTemplate(template *TimerTemplate, language string) string {
+	lines := []string{
+		template.Message,
+		tr(language, "template.time", template.Time),
+	}
+	if template.Channel != "" {
+		lines = append(lines, tr(language, "template.channel", template.Channel))
+	}
+	if template.FollowUps != "" {
+		lines = append(lines, tr(language, "template.follow_ups", template.FollowUps))
+	}
+	if len(template.Tags) > 0 {
+		lines = append(lines, formatTags(template.Tags))
+	}
+	if template.Important {
+		lines = append(lines, tr(language, "template.important"))
+	}
+	return truncateField(strings.Join(lines, "\n"))
+}
+
+// truncateField keeps a value below the embed field limit of 1024 characters
+func truncateField(value string) string {
+	const maxLen = 1024
+	runes := []rune(value)
+	if len(runes) <= maxLen {
+		return value
+	}
+	return string(runes[:maxLen-1]) + "…"
+}
+
+func createTemplateListEmbed(templates []*TimerTemplate, language string) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: tr(language, "template.list.title"),
+		Color: 0x3c1984,
+	}
+	for _, template := range templates {
+		if len(embed.Fields) == maxListedTimers {
+			embed.Footer = &discordgo.MessageEmbedFooter{Text: tr(language, "list.more", len(templates)-maxListedTimers)}
+			break
+		}
+		name := template.Name
+		if template.Scope == SettingScopeGuild {
+			name += " " + tr(language, "template.guild")
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  name,
+			Value: describeTemplate(template, language),
+		})
+	}
+	return embed
+}
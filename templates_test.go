@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTemplateName(t *testing.T) {
+	name, err := normalizeTemplateName(" Standup ")
+	require.NoError(t, err)
+	assert.Equal(t, "standup", name)
+
+	name, err = normalizeTemplateName("weekly_review-2")
+	require.NoError(t, err)
+	assert.Equal(t, "weekly_review-2", name)
+
+	for _, invalid := range []string{"", "two words", "#standup", strings.Repeat("a", maxTemplateNameLength+1)} {
+		_, err = normalizeTemplateName(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestDescribeTemplate(t *testing.T) {
+	template := &TimerTemplate{Name: "standup", Message: "Standup", Time: "monday 09:00"}
+	assert.Equal(t, "Standup\nTime: monday 09:00", describeTemplate(template, LanguageEnglish))
+
+	template.Channel = "42"
+	template.FollowUps = "15m: notes"
+	template.Tags = []string{"team"}
+	template.Important = true
+	assert.Equal(t, "Standup\nTime: monday 09:00\nChannel: <#42>\nThen: 15m: notes\n`#team`\nImportant", describeTemplate(template, LanguageEnglish))
+
+	template.Message = strings.Repeat("ä", 2000)
+	assert.Len(t, []rune(describeTemplate(template, LanguageEnglish)), 1024)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// pendingTimeChoice remembers what to do once the user picked one of several
+// interpretations of an ambiguous time
+type pendingTimeChoice struct {
+	kind    string
+	userID  string
+	timerID string
+	// message is the message of a timer that is about to be created
+	message string
+	// timerOptions are the optional settings of a timer that is about to be created
+	timerOptions TimerOptions
+	// newMessage and newTags are the optional changes of an edited timer
+	newMessage *string
+	newTags    *[]string
+	// snoozeFromDue counts a snooze from the original due time
+	snoozeFromDue bool
+	expires       time.Time
+}
+
+const pendingTimeChoiceLifetime = 15 * time.Minute
+
+var (
+	pendingTimeChoices      = make(map[string]*pendingTimeChoice)
+	pendingTimeChoicesMutex sync.Mutex
+)
+
+// resolveTimeInput parses the time typed by the user. If the input is
+// ambiguous the user is asked to pick an interpretation and pending is
+// completed once they do. The returned bool is false whenever the
+// interaction has already been responded to.
+func resolveTimeInput(session *discordgo.Session, interaction *discordgo.InteractionCreate, timeStr string, pending *pendingTimeChoice) (time.Time, bool) {
+	language := getInteractionLanguage(interaction)
+
+	candidates, err := parseTimeCandidates(timeStr, getParseOptions(interaction))
+	if err != nil {
+		respondWithError(session, interaction.Interaction, describeParseError(err, language), "resolveTimeInput() parsing time", err)
+		return time.Time{}, false
+	}
+
+	now := time.Now()
+	if choices := ambiguousCandidates(candidates, now); choices != nil {
+		promptTimeChoice(session, interaction, timeStr, choices, pending, language)
+		return time.Time{}, false
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Time.After(now) {
+			return candidate.Time, true
+		}
+	}
+
+	respondWithError(session, interaction.Interaction, tr(language, "error.time_in_past", timeStr, formatTime(candidates[0].Time, false, language)), "resolveTimeInput() time in past", nil)
+	return time.Time{}, false
+}
+
+// describeParseError explains to the user why their input was rejected
+func describeParseError(err error, language string) string {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		switch parseErr.Kind {
+		case ParseErrorEmpty:
+			return tr(language, "error.time_empty")
+		case ParseErrorNotUnderstood:
+			return tr(language, "error.time_not_understood", parseErr.Input)
+		}
+	}
+	return tr(language, "error.invalid_date")
+}
+
+func promptTimeChoice(session *discordgo.Session, interaction *discordgo.InteractionCreate, timeStr string, candidates []ParseCandidate, pending *pendingTimeChoice, language string) {
+	token := randomString(12)
+	pending.userID = getUserFromInteraction(interaction).ID
+	pending.expires = time.Now().Add(pendingTimeChoiceLifetime)
+
+	pendingTimeChoicesMutex.Lock()
+	for key, other := range pendingTimeChoices {
+		if time.Now().After(other.expires) {
+			delete(pendingTimeChoices, key)
+		}
+	}
+	pendingTimeChoices[token] = pending
+	pendingTimeChoicesMutex.Unlock()
+
+	options := make([]discordgo.SelectMenuOption, 0, len(candidates))
+	for _, candidate := range candidates {
+		if len(options) == 25 {
+			break
+		}
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       formatTime(candidate.Time, false, language),
+			Value:       strconv.FormatInt(candidate.Time.Unix(), 10),
+			Description: truncateChoice(fmt.Sprintf("%s, %s", tr(language, "reason."+string(candidate.Reason)), humanizeTime(candidate.Time, language))),
+		})
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: tr(language, "choice.prompt", timeStr),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.SelectMenu{
+							CustomID:    "time_choice:" + token,
+							Placeholder: tr(language, "choice.placeholder"),
+							Options:     options,
+						},
+					},
+				},
+			},
+		},
+	}, "promptTimeChoice()")
+}
+
+// handleTimeChoice completes the command that was waiting for the user to
+// pick a time
+func handleTimeChoice(session *discordgo.Session, interaction *discordgo.InteractionCreate, token string) {
+	language := getInteractionLanguage(interaction)
+
+	pendingTimeChoicesMutex.Lock()
+	pending, ok := pendingTimeChoices[token]
+	if ok && pending.userID == getUserFromInteraction(interaction).ID {
+		delete(pendingTimeChoices, token)
+	}
+	pendingTimeChoicesMutex.Unlock()
+
+	if !ok || time.Now().After(pending.expires) {
+		respondWithError(session, interaction.Interaction, tr(language, "choice.expired"), "handleTimeChoice() expired", nil)
+		return
+	}
+	if pending.userID != getUserFromInteraction(interaction).ID {
+		respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: tr(language, "choice.not_yours"),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		}, "handleTimeChoice() not yours")
+		return
+	}
+
+	values := interaction.MessageComponentData().Values
+	if len(values) == 0 {
+		return
+	}
+	unix, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.invalid_date"), "handleTimeChoice() invalid value", err)
+		return
+	}
+	date := time.Unix(unix, 0).Local()
+
+	switch pending.kind {
+	case "create":
+		completeTimerCreate(session, interaction, pending.message, date, pending.timerOptions, discordgo.InteractionResponseUpdateMessage)
+	case "edit":
+		completeTimerEdit(session, interaction, pending.timerID, pending.newMessage, pending.newTags, &date, discordgo.InteractionResponseUpdateMessage)
+	case "snooze":
+		completeTimerSnooze(session, interaction, pending.timerID, date, pending.snoozeFromDue, discordgo.InteractionResponseUpdateMessage)
+	case "dnd":
+		completeDNDStart(session, interaction, date, discordgo.InteractionResponseUpdateMessage)
+	}
+}
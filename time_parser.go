@@ -1,22 +1,261 @@
 package main
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/markusmobius/go-dateparser"
 )
 
+// ParseOptions controls how ambiguous or non-English input is interpreted
+type ParseOptions struct {
+	// DateOrder is the order of numeric dates like 03/04, one of DMY, MDY or YMD
+	DateOrder string
+	// Languages are the languages tried for natural language input, empty means auto-detect
+	Languages []string
+	// StrictDateOrder is set when the user chose the date order, numeric dates
+	// are then never offered in the other order
+	StrictDateOrder bool
+	// DefaultTimeOfDay is used when the input has no time, either "now" or a
+	// time like "09:00"
+	DefaultTimeOfDay string
+	// NamedTimes maps words like "lunch" to a time of day like "12:30", they
+	// take precedence over defaultNamedTimes
+	NamedTimes map[string]string
+	// Calendar decides which days count for "in 3 business days" and similar
+	Calendar BusinessCalendar
+}
+
+// defaultNamedTimes are the times of day understood without any settings
+var defaultNamedTimes = map[string]string{
+	"morning":     "08:00",
+	"noon":        "12:00",
+	"lunch":       "12:30",
+	"afternoon":   "15:00",
+	"eod":         "17:30",
+	"evening":     "18:00",
+	"night":       "21:00",
+	"morgens":     "08:00",
+	"mittags":     "12:00",
+	"nachmittags": "15:00",
+	"feierabend":  "17:30",
+	"abends":      "18:00",
+	"nachts":      "21:00",
+}
+
+// parseNamedTimes parses a list like "lunch=12:30, standup=09:15"
+func parseNamedTimes(value string) (map[string]string, error) {
+	namedTimes := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, clock, found := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		clock = strings.TrimSpace(clock)
+		if !found || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("expected entries like lunch=12:30, got %q", strings.TrimSpace(entry))
+		}
+		if _, _, ok, err := parseTimeOfDay(clock); err != nil || !ok {
+			return nil, fmt.Errorf("invalid time %q for %q, expected a time like 12:30", clock, name)
+		}
+		namedTimes[name] = clock
+	}
+	return namedTimes, nil
+}
+
+// formatNamedTimes is the inverse of parseNamedTimes with a stable order
+func formatNamedTimes(namedTimes map[string]string) string {
+	entries := make([]string, 0, len(namedTimes))
+	for _, name := range slices.Sorted(maps.Keys(namedTimes)) {
+		entries = append(entries, name+"="+namedTimes[name])
+	}
+	return strings.Join(entries, ",")
+}
+
+var dateOrders = map[string]dateparser.DateOrder{
+	"DMY": dateparser.DMY,
+	"MDY": dateparser.MDY,
+	"YMD": dateparser.YMD,
+}
+
+func defaultParseOptions() ParseOptions {
+	return ParseOptions{DateOrder: "DMY", DefaultTimeOfDay: "now", Calendar: defaultBusinessCalendar()}
+}
+
+// parseTimeOfDay parses a default time of day like "09:00", "now" yields false
+func parseTimeOfDay(value string) (hour int, minute int, ok bool, err error) {
+	if value == "" || strings.EqualFold(value, "now") {
+		return 0, 0, false, nil
+	}
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("expected a time like 09:00 or now, got %q", value)
+	}
+	return parsed.Hour(), parsed.Minute(), true, nil
+}
+
+// validateDateLanguages checks that the parser knows all the given language codes
+func validateDateLanguages(languages []string) error {
+	_, err := dateparser.Parse(&dateparser.Configuration{Languages: languages}, "2000-01-01")
+	return err
+}
+
+// parseDateLanguages splits a comma separated list like "en, de" into language codes
+func parseDateLanguages(value string) []string {
+	var languages []string
+	for _, language := range strings.Split(value, ",") {
+		language = strings.ToLower(strings.TrimSpace(language))
+		if language != "" {
+			languages = append(languages, language)
+		}
+	}
+	return languages
+}
+
+// ParseReason describes how an interpretation of the input was found
+type ParseReason string
+
+const (
+	ReasonStandardFormat ParseReason = "standard_format"
+	ReasonAsWritten      ParseReason = "as_written"
+	ReasonInPrefix       ParseReason = "in_prefix"
+	ReasonOnPrefix       ParseReason = "on_prefix"
+	ReasonNextPrefix     ParseReason = "next_prefix"
+	ReasonPast           ParseReason = "past"
+	ReasonDateOrder      ParseReason = "date_order"
+)
+
+// ParsedComponents tells which parts of a time the user actually typed
+type ParsedComponents struct {
+	Date bool
+	Time bool
+	Zone bool
+}
+
+// ParseCandidate is one possible interpretation of the user's input
+type ParseCandidate struct {
+	Time       time.Time
+	Reason     ParseReason
+	Confidence float64
+	Components ParsedComponents
+}
+
+type ParseErrorKind int
+
+const (
+	ParseErrorEmpty ParseErrorKind = iota
+	ParseErrorNotUnderstood
+)
+
+// ParseError is returned when the input can't be interpreted at all
+type ParseError struct {
+	Kind  ParseErrorKind
+	Input string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	if e.Kind == ParseErrorEmpty {
+		return "no time given"
+	}
+	return fmt.Sprintf("could not understand %q: %v", e.Input, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 func parseTime(timeStr string) (time.Time, error) {
-	parsed, err := parseTimeInternal(timeStr)
+	return parseTimeWithOptions(timeStr, defaultParseOptions())
+}
+
+// parseTimeWithOptions returns the most likely interpretation of timeStr
+func parseTimeWithOptions(timeStr string, options ParseOptions) (time.Time, error) {
+	candidates, err := parseTimeCandidates(timeStr, options)
 	if err != nil {
 		return time.Time{}, err
 	}
 
-	if parsed.Hour() == 0 && parsed.Minute() == 0 {
-		// The user typed just a date without specifying a time
-		// Default to using the current time
+	return candidates[0].Time, nil
+}
+
+// parseTimeCandidates returns all distinct interpretations of timeStr, the
+// most likely one first
+func parseTimeCandidates(timeStr string, options ParseOptions) ([]ParseCandidate, error) {
+	if strings.TrimSpace(timeStr) == "" {
+		return nil, &ParseError{Kind: ParseErrorEmpty, Input: timeStr}
+	}
+
+	candidates, err := parseTimeInternal(timeStr, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range candidates {
+		candidates[i].Time, err = applyDefaultTimeOfDay(candidates[i], options.DefaultTimeOfDay)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b ParseCandidate) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+
+	// Different ways of reading the input often lead to the same time
+	distinct := candidates[:0]
+	for _, candidate := range candidates {
+		duplicate := slices.ContainsFunc(distinct, func(other ParseCandidate) bool {
+			return other.Time.Truncate(time.Minute).Equal(candidate.Time.Truncate(time.Minute))
+		})
+		if !duplicate {
+			distinct = append(distinct, candidate)
+		}
+	}
+
+	return distinct, nil
+}
+
+// ambiguousCandidates returns the candidates the user should choose from, or
+// nil if there is at most one plausible interpretation in the future
+func ambiguousCandidates(candidates []ParseCandidate, now time.Time) []ParseCandidate {
+	var future []ParseCandidate
+	for _, candidate := range candidates {
+		if candidate.Time.After(now) {
+			future = append(future, candidate)
+		}
+	}
+
+	if len(future) < 2 {
+		return nil
+	}
+	return future
+}
+
+// applyDefaultTimeOfDay sets the time of day of candidates for which the
+// user only gave a date
+func applyDefaultTimeOfDay(candidate ParseCandidate, defaultTimeOfDay string) (time.Time, error) {
+	parsed := candidate.Time
+	if candidate.Components.Time {
+		return parsed, nil
+	}
+
+	hour, minute, ok, err := parseTimeOfDay(defaultTimeOfDay)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !ok {
 		timeNow := time.Now()
-		parsed = time.Date(
+		return time.Date(
 			parsed.Year(),
 			parsed.Month(),
 			parsed.Day(),
@@ -25,72 +264,397 @@ func parseTime(timeStr string) (time.Time, error) {
 			timeNow.Second(),
 			timeNow.Nanosecond(),
 			parsed.Location(),
-		)
+		), nil
+	}
+
+	return time.Date(parsed.Year(), parsed.Month(), parsed.Day(), hour, minute, 0, 0, parsed.Location()), nil
+}
+
+// timeOfDayPattern matches an explicit time of day like 9:30, 3pm, 9 Uhr or midnight
+var timeOfDayPattern = regexp.MustCompile(`(?i)\b\d{1,2}:\d{2}\b|\b\d{1,2}\s*(?:am|pm|uhr)\b|\b(?:noon|midday|midnight|mittag|mitternacht)\b`)
+
+// shortDurationPattern matches durations shorter than a day, which are
+// counted from the current time and so already determine the time of day
+var shortDurationPattern = regexp.MustCompile(`(?i)\b(?:\d+|an?|one|eine[rn]?)\s*(?:s|secs?|seconds?|m|mins?|minutes?|h|hrs?|hours?|sekunden?|minuten?|stunden?)\b`)
+
+var timeOnlyFillerPattern = regexp.MustCompile(`(?i)\b(?:at|um|today|heute)\b`)
+
+// detectComponents works out which parts of a time the input specifies.
+// Whether a time zone was given is only known to the parser, so the caller
+// fills that in.
+func detectComponents(timeStr string) ParsedComponents {
+	components := ParsedComponents{Date: true}
+	components.Time = timeOfDayPattern.MatchString(timeStr) || shortDurationPattern.MatchString(timeStr)
+
+	// Inputs like "15:00" or "at noon" only give a time, the parser picks the date
+	if components.Time {
+		rest := timeOfDayPattern.ReplaceAllString(timeStr, "")
+		rest = timeOnlyFillerPattern.ReplaceAllString(rest, "")
+		components.Date = strings.TrimSpace(rest) != ""
 	}
 
-	return parsed, nil
+	return components
 }
 
-func parseTimeInternal(timeStr string) (time.Time, error) {
+// compactDurationPattern matches durations like 1h30m, 2d4h or in 90s
+var compactDurationPattern = regexp.MustCompile(`(?i)^\s*(?:in\s+)?((?:\d+\s*[wdhms]\s*)+)$`)
+
+var compactDurationPartPattern = regexp.MustCompile(`(?i)(\d+)\s*([wdhms])`)
+
+func parseCompactDuration(timeStr string) (time.Duration, bool) {
+	match := compactDurationPattern.FindStringSubmatch(timeStr)
+	if match == nil {
+		return 0, false
+	}
+
+	units := map[string]time.Duration{
+		"w": 7 * 24 * time.Hour,
+		"d": 24 * time.Hour,
+		"h": time.Hour,
+		"m": time.Minute,
+		"s": time.Second,
+	}
+
+	var total time.Duration
+	for _, part := range compactDurationPartPattern.FindAllStringSubmatch(match[1], -1) {
+		n, err := strconv.Atoi(part[1])
+		if err != nil {
+			return 0, false
+		}
+		total += time.Duration(n) * units[strings.ToLower(part[2])]
+	}
+	return total, total > 0
+}
+
+// durationPattern matches inputs that are only a duration, like "2 hours",
+// "in 1 day and 3 hours" or "eine Woche"
+var durationPattern = regexp.MustCompile(`(?i)^\s*(?:in\s+)?(?:(?:\d+|an?|one|eine[rnm]?)\s*(?:s|secs?|seconds?|m|mins?|minutes?|h|hrs?|hours?|d|days?|w|weeks?|months?|sekunden?|minuten?|stunden?|tage?n?|wochen?|monate?n?)\s*(?:,|and|und)?\s*)+$`)
+
+// isDuration reports whether the input is a duration instead of a point in time
+func isDuration(timeStr string) bool {
+	if _, ok := parseCompactDuration(timeStr); ok {
+		return true
+	}
+	return durationPattern.MatchString(timeStr)
+}
+
+// clockPattern matches a time of day written with digits, like 9:30, 3pm or 9 Uhr
+const clockPattern = `\d{1,2}:\d{2}(?:\s*(?:am|pm))?|\d{1,2}\s*(?:am|pm|uhr)`
+
+var bareHourPattern = regexp.MustCompile(`(?i)^(.*?)\s*\b(?:at|um)\s+(\d{1,2})\s*$`)
+
+// timeOfDayPatterns caches the pattern of splitTimeOfDay for each set of
+// named times, as it runs on every keystroke of the autocomplete
+var timeOfDayPatterns sync.Map
+
+func timeOfDayPatternFor(names []string) *regexp.Regexp {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	alternatives := strings.Join(quoted, "|")
+	if pattern, ok := timeOfDayPatterns.Load(alternatives); ok {
+		return pattern.(*regexp.Regexp)
+	}
+
+	pattern := regexp.MustCompile(`(?i)^(.*?)\s*(?:\b(?:at|um|in the|gegen)\s+)?\b(` + clockPattern + `|` + alternatives + `)\s*$`)
+	timeOfDayPatterns.Store(alternatives, pattern)
+	return pattern
+}
+
+// splitTimeOfDay splits input like "in 2 weeks at 9am" or "friday evening"
+// into the date part and the time of day at its end
+func splitTimeOfDay(timeStr string, namedTimes map[string]string) (datePart string, hour int, minute int, ok bool) {
+	names := slices.Collect(maps.Keys(defaultNamedTimes))
+	for name := range namedTimes {
+		if _, exists := defaultNamedTimes[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	// Prefer the longest name so that "afternoon" isn't matched as "noon"
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), cmp.Compare(a, b))
+	})
+
+	if match := timeOfDayPatternFor(names).FindStringSubmatch(timeStr); match != nil {
+		clock := strings.ToLower(match[2])
+		if named, exists := namedTimes[clock]; exists {
+			clock = named
+		} else if named, exists := defaultNamedTimes[clock]; exists {
+			clock = named
+		}
+
+		hour, minute, ok := parseClock(clock)
+		if ok {
+			return strings.TrimSpace(match[1]), hour, minute, true
+		}
+	}
+
+	if match := bareHourPattern.FindStringSubmatch(timeStr); match != nil {
+		hour, _ := strconv.Atoi(match[2])
+		if hour < 24 {
+			return strings.TrimSpace(match[1]), hour, 0, true
+		}
+	}
+
+	return "", 0, 0, false
+}
+
+var clockPartsPattern = regexp.MustCompile(`(?i)^(\d{1,2})(?::(\d{2}))?\s*(am|pm|uhr)?$`)
+
+// parseClock parses a time of day like "9:30", "3pm", "12am" or "9 uhr"
+func parseClock(clock string) (hour int, minute int, ok bool) {
+	match := clockPartsPattern.FindStringSubmatch(strings.TrimSpace(clock))
+	if match == nil {
+		return 0, 0, false
+	}
+
+	hour, _ = strconv.Atoi(match[1])
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+
+	switch strings.ToLower(match[3]) {
+	case "am":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		if hour != 12 {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+// parseWithTimeOfDay parses the date part on its own and moves the results
+// to the given time of day
+func parseWithTimeOfDay(timeStr string, datePart string, hour int, minute int, options ParseOptions) ([]ParseCandidate, error) {
+	now := time.Now()
+	components := ParsedComponents{Date: datePart != "", Time: true}
+
+	lowerDatePart := strings.ToLower(datePart)
+	if datePart == "" || lowerDatePart == "today" || lowerDatePart == "heute" {
+		date := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, time.Local)
+		// A bare time that already passed today means tomorrow
+		if datePart == "" && !date.After(now) {
+			date = date.AddDate(0, 0, 1)
+		}
+		reason := ReasonAsWritten
+		if !date.After(now) {
+			reason = ReasonPast
+		}
+		return []ParseCandidate{{Time: date, Reason: reason, Confidence: 0.9, Components: components}}, nil
+	}
+
+	candidates, err := parseTimeInternal(datePart, options)
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			parseErr.Input = timeStr
+		}
+		return nil, err
+	}
+
+	for i, candidate := range candidates {
+		date := candidate.Time
+		candidates[i].Time = time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, date.Location())
+		candidates[i].Components.Time = true
+	}
+	return candidates, nil
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"monday":     time.Monday,
+	"tuesday":    time.Tuesday,
+	"wednesday":  time.Wednesday,
+	"thursday":   time.Thursday,
+	"friday":     time.Friday,
+	"saturday":   time.Saturday,
+	"sunday":     time.Sunday,
+	"montag":     time.Monday,
+	"dienstag":   time.Tuesday,
+	"mittwoch":   time.Wednesday,
+	"donnerstag": time.Thursday,
+	"freitag":    time.Friday,
+	"samstag":    time.Saturday,
+	"sonntag":    time.Sunday,
+}
+
+var relativeWeekdayPattern = regexp.MustCompile(`(?i)^\s*(?:next|this|coming|on|nächste[nr]?|kommende[nr]?|diese[nr]?|am)\s+(\p{L}+)\s*$`)
+
+// parseRelativeWeekday handles "next friday" and similar, which go-dateparser
+// doesn't understand. It returns the next such weekday after today.
+func parseRelativeWeekday(timeStr string, now time.Time) (time.Time, bool) {
+	match := relativeWeekdayPattern.FindStringSubmatch(timeStr)
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	weekday, ok := weekdayNames[strings.ToLower(match[1])]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	days := (int(weekday) - int(now.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	date := now.AddDate(0, 0, days)
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local), true
+}
+
+// numericDatePattern matches dates like 03/04 or 3.4.2030 whose day and month
+// could be swapped
+var numericDatePattern = regexp.MustCompile(`(?:^|\s)(\d{1,2})[/.\-](\d{1,2})(?:[/.\-]\d{2,4})?\.?(?:\s|$)`)
+
+// alternativeDateOrder returns the date order that reads timeStr differently,
+// if the numeric date in it is ambiguous
+func alternativeDateOrder(timeStr string, dateOrder string) (string, bool) {
+	match := numericDatePattern.FindStringSubmatch(timeStr)
+	if match == nil || match[1] == match[2] {
+		return "", false
+	}
+
+	first, _ := strconv.Atoi(match[1])
+	second, _ := strconv.Atoi(match[2])
+	if first < 1 || first > 12 || second < 1 || second > 12 {
+		return "", false
+	}
+
+	switch dateOrder {
+	case "DMY":
+		return "MDY", true
+	case "MDY":
+		return "DMY", true
+	}
+	return "", false
+}
+
+func parseTimeInternal(timeStr string, options ParseOptions) ([]ParseCandidate, error) {
 	// Try standard Go time formats first for well-formed ISO dates
-	formats := []string{
-		time.RFC3339,
-		time.RFC3339Nano,
-		"2006-01-02 15:04:05",
-		"2006-01-02 15:04",
-		"2006-01-02",
-		time.RFC1123,
-		time.RFC1123Z,
+	formats := []struct {
+		layout     string
+		components ParsedComponents
+	}{
+		{time.RFC3339, ParsedComponents{Date: true, Time: true, Zone: true}},
+		{time.RFC3339Nano, ParsedComponents{Date: true, Time: true, Zone: true}},
+		{"2006-01-02 15:04:05", ParsedComponents{Date: true, Time: true}},
+		{"2006-01-02 15:04", ParsedComponents{Date: true, Time: true}},
+		{"2006-01-02", ParsedComponents{Date: true}},
+		{time.RFC1123, ParsedComponents{Date: true, Time: true, Zone: true}},
+		{time.RFC1123Z, ParsedComponents{Date: true, Time: true, Zone: true}},
 	}
 
 	for _, format := range formats {
-		parsedTime, parseErr := time.Parse(format, timeStr)
+		if format.components.Zone {
+			parsedTime, parseErr := time.Parse(format.layout, timeStr)
+			if parseErr == nil {
+				// Convert to local timezone
+				return []ParseCandidate{{Time: parsedTime.Local(), Reason: ReasonStandardFormat, Confidence: 1, Components: format.components}}, nil
+			}
+			continue
+		}
+
+		// Without a zone in the input the time is meant in local time
+		parsedTime, parseErr := time.ParseInLocation(format.layout, timeStr, time.Local)
 		if parseErr == nil {
-			// Convert to local timezone
-			return parsedTime.Local(), nil
+			return []ParseCandidate{{Time: parsedTime, Reason: ReasonStandardFormat, Confidence: 1, Components: format.components}}, nil
 		}
 	}
 
+	if duration, ok := parseCompactDuration(timeStr); ok {
+		return []ParseCandidate{{Time: time.Now().Add(duration), Reason: ReasonAsWritten, Confidence: 1, Components: ParsedComponents{Date: true, Time: true}}}, nil
+	}
+
+	if datePart, hour, minute, ok := splitTimeOfDay(timeStr, options.NamedTimes); ok {
+		return parseWithTimeOfDay(timeStr, datePart, hour, minute, options)
+	}
+
+	if date, ok := parseRelativeWeekday(timeStr, time.Now()); ok {
+		return []ParseCandidate{{Time: date, Reason: ReasonAsWritten, Confidence: 0.9, Components: ParsedComponents{Date: true}}}, nil
+	}
+
+	if date, ok := parseBusinessDays(timeStr, options.Calendar, time.Now()); ok {
+		return []ParseCandidate{{Time: date, Reason: ReasonAsWritten, Confidence: 0.9, Components: ParsedComponents{Date: true}}}, nil
+	}
+
 	// If standard formats fail, use dateparser for natural language
-	configuration := &dateparser.Configuration{
-		CurrentTime:         time.Now(),
-		DateOrder:           dateparser.DMY,
-		PreferredDateSource: dateparser.Future,
+	configuration, err := dateparserConfiguration(options.DateOrder, options.Languages)
+	if err != nil {
+		return nil, err
 	}
 
 	date, err := dateparser.Parse(configuration, timeStr)
-
 	if err != nil {
-		return time.Time{}, err
+		return nil, &ParseError{Kind: ParseErrorNotUnderstood, Input: timeStr, Err: err}
 	}
 
-	// If the parsed time is in the past, try adding "in" prefix
-	if time.Now().After(date.Time) {
-		dateWithIn, errIn := dateparser.Parse(configuration, "in "+timeStr)
-		if errIn == nil && time.Now().Before(dateWithIn.Time) {
-			// Convert to local timezone
-			return dateWithIn.Time.Local(), nil
+	components := detectComponents(timeStr)
+	components.Zone = date.Time.Location() != time.Local
+
+	now := time.Now()
+	var candidates []ParseCandidate
+	if date.Time.After(now) {
+		candidates = append(candidates, ParseCandidate{Time: date.Time.Local(), Reason: ReasonAsWritten, Confidence: 0.9, Components: components})
+	} else {
+		// The parsed time is in the past, try the prefixes a user might have
+		// left out, e.g. "in" for durations and "on" or "next" for weekday names
+		prefixes := []struct {
+			prefix     string
+			reason     ParseReason
+			confidence float64
+		}{
+			{"in ", ReasonInPrefix, 0.8},
+			{"on ", ReasonOnPrefix, 0.7},
+			{"next ", ReasonNextPrefix, 0.6},
 		}
+		for _, p := range prefixes {
+			prefixed, err := dateparser.Parse(configuration, p.prefix+timeStr)
+			if err == nil && now.Before(prefixed.Time) {
+				candidates = append(candidates, ParseCandidate{Time: prefixed.Time.Local(), Reason: p.reason, Confidence: p.confidence, Components: components})
+			}
+		}
+
+		// Keep the time as written as a last resort, callers decide whether
+		// a time in the past is acceptable
+		candidates = append(candidates, ParseCandidate{Time: date.Time.Local(), Reason: ReasonPast, Confidence: 0.1, Components: components})
 	}
 
-	// If still in the past, try adding "on" prefix for weekday names
-	if time.Now().After(date.Time) {
-		dateWithOn, errOn := dateparser.Parse(configuration, "on "+timeStr)
-		if errOn == nil && time.Now().Before(dateWithOn.Time) {
-			// Convert to local timezone
-			return dateWithOn.Time.Local(), nil
+	if alternative, ok := alternativeDateOrder(timeStr, options.DateOrder); ok && !options.StrictDateOrder {
+		alternativeConfiguration, err := dateparserConfiguration(alternative, options.Languages)
+		if err != nil {
+			return nil, err
+		}
+		swapped, err := dateparser.Parse(alternativeConfiguration, timeStr)
+		if err == nil {
+			candidates = append(candidates, ParseCandidate{Time: swapped.Time.Local(), Reason: ReasonDateOrder, Confidence: 0.4, Components: components})
 		}
 	}
 
-	// If still in the past, try "next" prefix for weekday names
-	if time.Now().After(date.Time) {
-		dateWithNext, errNext := dateparser.Parse(configuration, "next "+timeStr)
-		if errNext == nil && time.Now().Before(dateWithNext.Time) {
-			// Convert to local timezone
-			return dateWithNext.Time.Local(), nil
-		}
+	return candidates, nil
+}
+
+func dateparserConfiguration(dateOrder string, languages []string) (*dateparser.Configuration, error) {
+	order, ok := dateOrders[dateOrder]
+	if !ok {
+		return nil, fmt.Errorf("unknown date order %q", dateOrder)
 	}
 
-	// Convert to local timezone before returning
-	return date.Time.Local(), err
+	return &dateparser.Configuration{
+		CurrentTime:         time.Now(),
+		DateOrder:           order,
+		Languages:           languages,
+		PreferredDateSource: dateparser.Future,
+	}, nil
 }
@@ -344,3 +344,310 @@ func TestParseTimeReturnsLocalTimezone(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTimeWithOptions(t *testing.T) {
+	t.Run("date order", func(t *testing.T) {
+		testCases := []struct {
+			dateOrder string
+			input     string
+			month     time.Month
+			day       int
+		}{
+			{dateOrder: "DMY", input: "03/04/2030", month: time.April, day: 3},
+			{dateOrder: "MDY", input: "03/04/2030", month: time.March, day: 4},
+			{dateOrder: "YMD", input: "2030/04/03", month: time.April, day: 3},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.dateOrder, func(t *testing.T) {
+				options := defaultParseOptions()
+				options.DateOrder = tc.dateOrder
+
+				result, err := parseTimeWithOptions(tc.input, options)
+
+				require.NoError(t, err)
+				assert.Equal(t, 2030, result.Year())
+				assert.Equal(t, tc.month, result.Month())
+				assert.Equal(t, tc.day, result.Day())
+			})
+		}
+	})
+
+	t.Run("unknown date order", func(t *testing.T) {
+		options := defaultParseOptions()
+		options.DateOrder = "DDD"
+
+		_, err := parseTimeWithOptions("tomorrow", options)
+		assert.Error(t, err)
+	})
+
+	t.Run("german input", func(t *testing.T) {
+		options := defaultParseOptions()
+		options.Languages = []string{"de"}
+
+		now := time.Now()
+		result, err := parseTimeWithOptions("in 2 Stunden", options)
+
+		require.NoError(t, err)
+		assert.WithinDuration(t, now.Add(2*time.Hour), result, time.Minute)
+	})
+}
+
+func TestParseDateLanguages(t *testing.T) {
+	assert.Equal(t, []string{"de", "en"}, parseDateLanguages(" DE, en ,"))
+	assert.Empty(t, parseDateLanguages(""))
+
+	assert.NoError(t, validateDateLanguages([]string{"de", "en"}))
+	assert.Error(t, validateDateLanguages([]string{"xx"}))
+}
+
+func TestParseTimeCandidates(t *testing.T) {
+	t.Run("ambiguous numeric date offers both orders", func(t *testing.T) {
+		candidates, err := parseTimeCandidates("03/04/2030", defaultParseOptions())
+		require.NoError(t, err)
+		require.Len(t, candidates, 2)
+
+		assert.Equal(t, ReasonAsWritten, candidates[0].Reason)
+		assert.Equal(t, time.April, candidates[0].Time.Month())
+		assert.Equal(t, ReasonDateOrder, candidates[1].Reason)
+		assert.Equal(t, time.March, candidates[1].Time.Month())
+
+		assert.Len(t, ambiguousCandidates(candidates, time.Now()), 2)
+	})
+
+	t.Run("explicit date order is not second guessed", func(t *testing.T) {
+		options := defaultParseOptions()
+		options.StrictDateOrder = true
+
+		candidates, err := parseTimeCandidates("03/04/2030", options)
+		require.NoError(t, err)
+		assert.Len(t, candidates, 1)
+		assert.Nil(t, ambiguousCandidates(candidates, time.Now()))
+	})
+
+	t.Run("unambiguous input has a single candidate", func(t *testing.T) {
+		testCases := []string{"in 2 hours", "tomorrow", "2030-12-24 18:00", "13/04/2030"}
+
+		for _, input := range testCases {
+			t.Run(input, func(t *testing.T) {
+				candidates, err := parseTimeCandidates(input, defaultParseOptions())
+				require.NoError(t, err)
+				assert.Len(t, candidates, 1)
+				assert.Nil(t, ambiguousCandidates(candidates, time.Now()))
+			})
+		}
+	})
+
+	t.Run("errors explain what went wrong", func(t *testing.T) {
+		var parseErr *ParseError
+
+		_, err := parseTimeCandidates("  ", defaultParseOptions())
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, ParseErrorEmpty, parseErr.Kind)
+
+		_, err = parseTimeCandidates("not a date", defaultParseOptions())
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, ParseErrorNotUnderstood, parseErr.Kind)
+		assert.Equal(t, "not a date", parseErr.Input)
+	})
+}
+
+func TestParseTimeDefaultTimeOfDay(t *testing.T) {
+	t.Run("explicit midnight is kept", func(t *testing.T) {
+		testCases := []string{"tomorrow at midnight", "tomorrow 00:00", "2030-12-31 00:00"}
+
+		for _, input := range testCases {
+			t.Run(input, func(t *testing.T) {
+				result, err := parseTime(input)
+
+				require.NoError(t, err)
+				assert.Equal(t, 0, result.Hour())
+				assert.Equal(t, 0, result.Minute())
+			})
+		}
+	})
+
+	t.Run("date without time uses the default time of day", func(t *testing.T) {
+		options := defaultParseOptions()
+		options.DefaultTimeOfDay = "09:00"
+
+		testCases := []string{"tomorrow", "friday", "2030-12-31", "31/12/2030"}
+
+		for _, input := range testCases {
+			t.Run(input, func(t *testing.T) {
+				result, err := parseTimeWithOptions(input, options)
+
+				require.NoError(t, err)
+				assert.Equal(t, 9, result.Hour())
+				assert.Equal(t, 0, result.Minute())
+			})
+		}
+	})
+
+	t.Run("date without time defaults to now", func(t *testing.T) {
+		now := time.Now()
+		result, err := parseTime("2030-12-31")
+
+		require.NoError(t, err)
+		assert.Equal(t, now.Hour(), result.Hour())
+		assert.Equal(t, now.Minute(), result.Minute())
+	})
+
+	t.Run("durations are not moved to the default time", func(t *testing.T) {
+		options := defaultParseOptions()
+		options.DefaultTimeOfDay = "09:00"
+
+		now := time.Now()
+		result, err := parseTimeWithOptions("in 2 hours", options)
+
+		require.NoError(t, err)
+		assert.WithinDuration(t, now.Add(2*time.Hour), result, time.Minute)
+	})
+
+	t.Run("invalid default time", func(t *testing.T) {
+		options := defaultParseOptions()
+		options.DefaultTimeOfDay = "breakfast"
+
+		_, err := parseTimeWithOptions("tomorrow", options)
+		assert.Error(t, err)
+	})
+}
+
+func TestDetectComponents(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected ParsedComponents
+	}{
+		{input: "tomorrow", expected: ParsedComponents{Date: true}},
+		{input: "tomorrow at 9:30", expected: ParsedComponents{Date: true, Time: true}},
+		{input: "friday 3pm", expected: ParsedComponents{Date: true, Time: true}},
+		{input: "15:00", expected: ParsedComponents{Time: true}},
+		{input: "at midnight", expected: ParsedComponents{Time: true}},
+		{input: "in 5 minutes", expected: ParsedComponents{Date: true, Time: true}},
+		{input: "in 2 days", expected: ParsedComponents{Date: true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, detectComponents(tc.input))
+		})
+	}
+}
+
+func TestParseCompactDurations(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{input: "1h30m", expected: 90 * time.Minute},
+		{input: "2d4h", expected: 52 * time.Hour},
+		{input: "90s", expected: 90 * time.Second},
+		{input: "in 1h30m", expected: 90 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			now := time.Now()
+			result, err := parseTime(tc.input)
+
+			require.NoError(t, err)
+			assert.WithinDuration(t, now.Add(tc.expected), result, 5*time.Second)
+		})
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	t.Run("date with clock time", func(t *testing.T) {
+		result, err := parseTime("in 2 weeks at 9am")
+
+		require.NoError(t, err)
+		assert.Equal(t, 9, result.Hour())
+		assert.Equal(t, 0, result.Minute())
+		assert.True(t, result.After(time.Now().AddDate(0, 0, 13)))
+	})
+
+	t.Run("relative weekday with named time", func(t *testing.T) {
+		result, err := parseTime("next friday evening")
+
+		require.NoError(t, err)
+		assert.Equal(t, time.Friday, result.Weekday())
+		assert.Equal(t, 18, result.Hour())
+		assert.Equal(t, 0, result.Minute())
+	})
+
+	t.Run("named time alone", func(t *testing.T) {
+		result, err := parseTime("eod")
+
+		require.NoError(t, err)
+		assert.Equal(t, 17, result.Hour())
+		assert.Equal(t, 30, result.Minute())
+		assert.True(t, result.After(time.Now()))
+	})
+
+	t.Run("custom named time", func(t *testing.T) {
+		options := defaultParseOptions()
+		options.NamedTimes = map[string]string{"standup": "09:15"}
+
+		result, err := parseTimeWithOptions("tomorrow standup", options)
+
+		require.NoError(t, err)
+		assert.Equal(t, 9, result.Hour())
+		assert.Equal(t, 15, result.Minute())
+	})
+}
+
+func TestParseClock(t *testing.T) {
+	testCases := []struct {
+		input  string
+		hour   int
+		minute int
+		ok     bool
+	}{
+		{input: "9am", hour: 9, minute: 0, ok: true},
+		{input: "12am", hour: 0, minute: 0, ok: true},
+		{input: "3:45pm", hour: 15, minute: 45, ok: true},
+		{input: "17:30", hour: 17, minute: 30, ok: true},
+		{input: "25:00", ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			hour, minute, ok := parseClock(tc.input)
+
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.hour, hour)
+				assert.Equal(t, tc.minute, minute)
+			}
+		})
+	}
+}
+
+func TestParseNamedTimes(t *testing.T) {
+	namedTimes, err := parseNamedTimes("Lunch=12:30, standup=09:15")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"lunch": "12:30", "standup": "09:15"}, namedTimes)
+	assert.Equal(t, "lunch=12:30,standup=09:15", formatNamedTimes(namedTimes))
+
+	for _, input := range []string{"lunch", "lunch=later", "=12:00"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := parseNamedTimes(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTimeOfDayPatternIsCached(t *testing.T) {
+	pattern := timeOfDayPatternFor([]string{"standup", "noon"})
+	assert.Same(t, pattern, timeOfDayPatternFor([]string{"standup", "noon"}))
+	assert.NotSame(t, pattern, timeOfDayPatternFor([]string{"noon"}))
+}
+
+func TestIsDuration(t *testing.T) {
+	for _, input := range []string{"2h", "in 1d4h", "2 hours", "in 1 day and 3 hours", "an hour", "eine Woche", "3 Tage", "90 minutes"} {
+		assert.True(t, isDuration(input), input)
+	}
+	for _, input := range []string{"friday 9am", "tomorrow", "2030-05-04", "at 3pm", "next week", "in 2 business days"} {
+		assert.False(t, isDuration(input), input)
+	}
+}
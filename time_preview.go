@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxChoiceLength is the most characters Discord allows in the name and
+// value of a choice
+const maxChoiceLength = 100
+
+// timePresets are suggested in the autocomplete of time options, in the
+// order they are shown
+var timePresets = map[string][]string{
+	LanguageEnglish: {"in 10 minutes", "in 30 minutes", "in 1 hour", "in 3 hours", "tomorrow", "in 2 days", "in 1 week"},
+	LanguageGerman:  {"in 10 Minuten", "in 30 Minuten", "in 1 Stunde", "in 3 Stunden", "morgen", "in 2 Tagen", "in 1 Woche"},
+}
+
+// handleTimeAutocomplete shows how the typed text is currently understood,
+// followed by matching presets
+func handleTimeAutocomplete(session *discordgo.Session, interaction *discordgo.InteractionCreate, input string, favorites []string) {
+	language := getInteractionLanguage(interaction)
+	choices := buildTimeSuggestions(input, favorites, getParseOptions(interaction), language, time.Now())
+	respondWithAutocompleteChoices(session, interaction.Interaction, choices)
+}
+
+// buildTimeSuggestions previews the input and suggests the favorites, like
+// the user's most common snoozes, followed by the presets of the language
+func buildTimeSuggestions(input string, favorites []string, options ParseOptions, language string, now time.Time) []*discordgo.ApplicationCommandOptionChoice {
+	input = strings.TrimSpace(input)
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, 25)
+
+	// A longer input can't be offered as a choice without changing it
+	if input != "" && utf8.RuneCountInString(input) <= maxChoiceLength {
+		parsed, err := parseTimeWithOptions(input, options)
+		name := buildTimePreviewLabel(input, parsed, language, now)
+		if err != nil {
+			name = truncateChoice(tr(language, "preview.invalid", input))
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  name,
+			Value: input,
+		})
+	}
+
+	presets := slices.Concat(favorites, timePresets[language])
+	if language != LanguageEnglish {
+		presets = slices.Concat(presets, timePresets[LanguageEnglish])
+	}
+	lowerInput := strings.ToLower(input)
+	for _, preset := range presets {
+		if len(choices) == 25 {
+			break
+		}
+		if strings.EqualFold(preset, input) || !strings.HasPrefix(strings.ToLower(preset), lowerInput) || slices.ContainsFunc(choices, func(choice *discordgo.ApplicationCommandOptionChoice) bool { return choice.Value == preset }) {
+			continue
+		}
+
+		parsed, err := parseTimeWithOptions(preset, options)
+		if err != nil {
+			// The preset is not understood with the user's parser settings
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  buildTimePreviewLabel(preset, parsed, language, now),
+			Value: preset,
+		})
+	}
+
+	return choices
+}
+
+// buildTimePreviewLabel renders e.g. "tomorrow 9 → Sat 18 Oct 09:00 (13 hours from now)"
+func buildTimePreviewLabel(input string, parsed time.Time, language string, now time.Time) string {
+	formatted := parsed.In(time.Local).Format("Mon 02 Jan 15:04")
+	if language == LanguageGerman {
+		formatted = parsed.In(time.Local).Format("02.01.2006 15:04")
+	}
+	if parsed.Year() != now.Year() && language != LanguageGerman {
+		formatted = parsed.In(time.Local).Format("Mon 02 Jan 2006 15:04")
+	}
+
+	suffix := fmt.Sprintf(" → %s (%s)", formatted, humanizeTime(parsed, language))
+	return truncateChoice(input, suffix)
+}
+
+// truncateChoice joins the parts and shortens the first one so that the
+// result fits into a choice
+func truncateChoice(text string, suffix ...string) string {
+	end := strings.Join(suffix, "")
+	runes := []rune(text)
+	available := maxChoiceLength - len([]rune(end))
+	if len(runes) <= available {
+		return text + end
+	}
+	if available <= 1 {
+		return string([]rune(end)[:maxChoiceLength])
+	}
+	return string(runes[:available-1]) + "…" + end
+}
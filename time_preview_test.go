@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func choiceValues(choices []*discordgo.ApplicationCommandOptionChoice) []string {
+	values := make([]string, len(choices))
+	for i, choice := range choices {
+		values[i] = fmt.Sprint(choice.Value)
+	}
+	return values
+}
+
+func TestBuildTimeSuggestions(t *testing.T) {
+	longInput := strings.Repeat("tomorrow ", 15)
+	manyFavorites := make([]string, 30)
+	for i := range manyFavorites {
+		manyFavorites[i] = fmt.Sprintf("in %d minutes", i+2)
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		favorites []string
+		language  string
+		expected  []string
+	}{
+		{
+			name:     "presets without input",
+			language: LanguageEnglish,
+			expected: timePresets[LanguageEnglish],
+		},
+		{
+			name:     "English presets follow the German ones",
+			language: LanguageGerman,
+			expected: append(append([]string{}, timePresets[LanguageGerman]...), timePresets[LanguageEnglish]...),
+		},
+		{
+			name:     "preview followed by matching presets",
+			input:    "in 1",
+			language: LanguageEnglish,
+			expected: []string{"in 1", "in 10 minutes", "in 1 hour", "in 1 week"},
+		},
+		{
+			name:     "preset equal to the input is not repeated",
+			input:    " Tomorrow ",
+			language: LanguageEnglish,
+			expected: []string{"Tomorrow"},
+		},
+		{
+			name:      "favorites come first and are not repeated as presets",
+			input:     "in 1 h",
+			favorites: []string{"in 1 hour", "in 1 hour"},
+			language:  LanguageEnglish,
+			expected:  []string{"in 1 h", "in 1 hour"},
+		},
+		{
+			name:      "at most 25 choices",
+			favorites: manyFavorites,
+			language:  LanguageEnglish,
+			expected:  manyFavorites[:25],
+		},
+		{
+			name:     "too long input is not offered",
+			input:    longInput,
+			language: LanguageEnglish,
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			choices := buildTimeSuggestions(test.input, test.favorites, defaultParseOptions(), test.language, time.Now())
+			assert.Equal(t, test.expected, choiceValues(choices))
+			for _, choice := range choices {
+				assert.LessOrEqual(t, utf8.RuneCountInString(choice.Name), maxChoiceLength)
+			}
+		})
+	}
+}
+
+func TestBuildTimeSuggestionsPreview(t *testing.T) {
+	choices := buildTimeSuggestions("in 2 hours", nil, defaultParseOptions(), LanguageEnglish, time.Now())
+	assert.True(t, strings.HasPrefix(choices[0].Name, "in 2 hours → "), choices[0].Name)
+	assert.True(t, strings.HasSuffix(choices[0].Name, " from now)"), choices[0].Name)
+
+	choices = buildTimeSuggestions("whenever", nil, defaultParseOptions(), LanguageEnglish, time.Now())
+	assert.Equal(t, "whenever → not understood", choices[0].Name)
+	assert.Equal(t, "whenever", choices[0].Value)
+}
+
+func TestTruncateChoice(t *testing.T) {
+	assert.Equal(t, "short → x", truncateChoice("short", " → x"))
+
+	long := truncateChoice(strings.Repeat("a", 150), " → suffix")
+	assert.Equal(t, maxChoiceLength, utf8.RuneCountInString(long))
+	assert.True(t, strings.HasSuffix(long, "… → suffix"))
+}
@@ -2,10 +2,10 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/dustin/go-humanize"
 )
 
 type Timer struct {
@@ -19,73 +19,186 @@ type Timer struct {
 	SnoozedDue  time.Time
 	SnoozeCount int
 	Shown       bool
+	// Language is the language the timer was created in, used when it is due
+	Language string
+	// FollowUps are the remaining steps of the chain, started one after the
+	// other each time the previous timer fires
+	FollowUps []FollowUp
+	// ChainID is the ID of the first timer of the chain this timer belongs to
+	ChainID       string
+	ChainPosition int
+	ChainLength   int
+	// PomodoroID is set for timers ending a phase of a pomodoro session
+	PomodoroID string
+	// MaxSnoozes limits how often the timer can be snoozed, 0 falls back to
+	// the setting of the guild
+	MaxSnoozes int
+	// DeletedAt is set once the timer was deleted, it can be restored until
+	// it is purged
+	DeletedAt *time.Time
+	Tags      []string
+	// AcknowledgedAt is set once someone confirmed they saw the due timer
+	AcknowledgedAt *time.Time
+	// RepingInterval marks an important timer, its owner is pinged again
+	// after this long until the timer is acknowledged
+	RepingInterval time.Duration
+	LastPing       *time.Time
+	// EscalateTo is the mention of a backup user or role pinged once the
+	// timer was not acknowledged EscalateAfter after it was due
+	EscalateTo    string
+	EscalateAfter time.Duration
+	EscalatedAt   *time.Time
+	// DeferredFrom is the time the timer was due at before quiet hours or a
+	// do-not-disturb of its owner pushed it back
+	DeferredFrom *time.Time
+	// Guild is the guild the timer was created in, empty in direct messages
+	// and for timers created before it was stored
+	Guild string
+	// Private timers are left out when listing the timers of a channel or
+	// guild, except for moderators
+	Private bool
+}
+
+// TimerOptions are the optional settings of a new timer
+type TimerOptions struct {
+	FollowUps      []FollowUp
+	MaxSnoozes     int
+	Tags           []string
+	RepingInterval time.Duration
+	EscalateTo     string
+	EscalateAfter  time.Duration
+	// Channel is where the timer is sent, empty for the channel it was
+	// created in
+	Channel string
+	Guild   string
+	Private bool
 }
 
 func checkDueTimers(session *discordgo.Session) {
 	timers, err := getDueTimers()
 	if err != nil {
-		fmt.Println("Error getting due timers:", err)
+		slog.Error("Getting due timers", "error", err)
 		return
 	}
 
 	for _, timer := range timers {
-		showDueTimer(session, timer)
-		err := markTimerAsShown(timer.ID)
+		silent := false
+		if timer.PomodoroID == "" {
+			quiet := getQuietSettings(timer.User, timer.Guild)
+			if until, ok := quiet.QuietUntil(time.Now()); ok {
+				if quiet.Mode != QuietModeSilent {
+					deferDueTimer(timer, until)
+					continue
+				}
+				silent = true
+			}
+		}
+
+		err := showDueTimer(session, timer, silent)
 		if err != nil {
-			fmt.Println("Error marking timer as shown:", err)
+			slog.Error("Showing due timer", "error", err)
+			recordTimerEvent(timer, TimerEventDeliveryFailed, "", map[string]string{"error": err.Error()})
+		} else {
+			recordTimerEvent(timer, TimerEventDelivered, "", map[string]string{"channel": timer.Channel})
+		}
+		err = markTimerAsShown(timer.ID)
+		if err != nil {
+			slog.Error("Marking timer as shown", "error", err)
+		}
+		startFollowUp(session, timer, time.Now())
+		if timer.PomodoroID != "" {
+			handlePomodoroTimerFired(timer, time.Now())
 		}
 	}
+
+	checkUnacknowledgedTimers(session, time.Now())
 }
 
-func showDueTimer(session *discordgo.Session, timer *Timer) {
-	user, err := session.User(timer.User)
+// deferDueTimer moves a timer that is due during the quiet hours of its
+// owner to the end of them
+func deferDueTimer(timer *Timer, until time.Time) {
+	err := deferTimer(timer.ID, until, timer.SnoozedDue)
 	if err != nil {
-		fmt.Println("Error getting user:", err)
+		slog.Error("Deferring timer", "error", err)
 		return
 	}
+	recordTimerEvent(timer, TimerEventDeferred, "", map[string]string{"oldDue": unixDetail(timer.SnoozedDue), "newDue": unixDetail(until)})
+}
 
-	embed := createTimerEmbed(timer, user, TimerEmbedTypeDue)
+// showDueTimer sends the notification of a due timer to its channel. Silent
+// notifications are sent during quiet hours and don't notify the owner.
+func showDueTimer(session *discordgo.Session, timer *Timer, silent bool) error {
+	user, err := session.User(timer.User)
+	if err != nil {
+		return fmt.Errorf("getting user: %w", err)
+	}
+
+	embed := createTimerEmbed(timer, user, TimerEmbedTypeDue, getTimerLanguage(timer))
 
-	_, err = session.ChannelMessageSendComplex(timer.Channel, &discordgo.MessageSend{
+	message := &discordgo.MessageSend{
 		Embeds:  []*discordgo.MessageEmbed{embed},
 		Content: user.Mention(),
-	})
+	}
+	if silent {
+		message.Flags = discordgo.MessageFlagsSuppressNotifications
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: tr(getTimerLanguage(timer), "quiet.silent")}
+	}
+	if timer.PomodoroID != "" {
+		pomodoro, err := getPomodoroByID(timer.PomodoroID)
+		if err != nil {
+			slog.Error("Getting pomodoro", "error", err)
+		} else {
+			message.Components = pomodoroComponents(pomodoro, getTimerLanguage(timer))
+		}
+	} else {
+		message.Components = acknowledgeComponents(timer, getTimerLanguage(timer))
+	}
+
+	_, err = session.ChannelMessageSendComplex(timer.Channel, message)
 	if err != nil {
-		fmt.Println("Error sending message:", err)
+		return fmt.Errorf("sending message: %w", err)
 	}
+	return nil
 }
 
 type TimerEmbedType struct {
-	Title                     string
+	titleKey                  string
 	color                     int
 	includeDurationForDue     bool
 	includeDurationForCreated bool
 }
 
 var (
-	TimerEmbedTypeCreation = TimerEmbedType{"Timer Created", 0x00ff00, true, false}
-	TimerEmbedTypeDeletion = TimerEmbedType{"Timer Deleted", 0xff0000, false, true}
-	TimerEmbedTypeSnooze   = TimerEmbedType{"Timer Snoozed", 0x00ffff, true, true}
-	TimerEmbedTypeEdit     = TimerEmbedType{"Timer Edited", 0xffff00, true, true}
-	TimerEmbedTypeDue      = TimerEmbedType{"Timer Due", 0x0000ff, false, true}
+	TimerEmbedTypeCreation       = TimerEmbedType{"embed.created", 0x00ff00, true, false}
+	TimerEmbedTypeDeletion       = TimerEmbedType{"embed.deleted", 0xff0000, false, true}
+	TimerEmbedTypeRestored       = TimerEmbedType{"embed.restored", 0x00ff00, true, true}
+	TimerEmbedTypeReping         = TimerEmbedType{"embed.reping", 0xff8c00, true, true}
+	TimerEmbedTypeAcknowledged   = TimerEmbedType{"embed.acknowledged", 0x00ff00, false, true}
+	TimerEmbedTypeUnacknowledged = TimerEmbedType{"embed.unacknowledged", 0xff0000, true, true}
+	TimerEmbedTypeSnooze         = TimerEmbedType{"embed.snoozed", 0x00ffff, true, true}
+	TimerEmbedTypeEdit           = TimerEmbedType{"embed.edited", 0xffff00, true, true}
+	TimerEmbedTypeDue            = TimerEmbedType{"embed.due", 0x0000ff, false, true}
+	TimerEmbedTypeFollowUp       = TimerEmbedType{"embed.follow_up", 0x00ff00, true, false}
+	TimerEmbedTypeEscalated      = TimerEmbedType{"embed.escalated", 0xff0000, true, true}
+	TimerEmbedTypeExpired        = TimerEmbedType{"embed.expired", 0x808080, false, true}
 )
 
-func createTimerEmbed(timer *Timer, owner *discordgo.User, embedType TimerEmbedType) *discordgo.MessageEmbed {
-	due := formatTime(timer.SnoozedDue, embedType.includeDurationForDue)
-	created := formatTime(timer.Created, embedType.includeDurationForCreated)
+func createTimerEmbed(timer *Timer, owner *discordgo.User, embedType TimerEmbedType, language string) *discordgo.MessageEmbed {
+	due := formatTime(timer.SnoozedDue, embedType.includeDurationForDue, language)
+	created := formatTime(timer.Created, embedType.includeDurationForCreated, language)
 
-	return &discordgo.MessageEmbed{
-		Title:       embedType.Title,
+	embed := &discordgo.MessageEmbed{
+		Title:       tr(language, embedType.titleKey),
 		Description: timer.Message,
 		Color:       embedType.color,
 		Fields: []*discordgo.MessageEmbedField{
 			{
-				Name:   "Id",
+				Name:   tr(language, "embed.field.id"),
 				Value:  timer.ID,
 				Inline: true,
 			},
 			{
-				Name:   "Owner",
+				Name:   tr(language, "embed.field.owner"),
 				Value:  owner.Mention(),
 				Inline: true,
 			},
@@ -95,26 +208,81 @@ func createTimerEmbed(timer *Timer, owner *discordgo.User, embedType TimerEmbedT
 				Inline: true,
 			},
 			{
-				Name:   "Due",
+				Name:   tr(language, "embed.field.due"),
 				Value:  due,
 				Inline: true,
 			},
 			{
-				Name:   "Created",
+				Name:   tr(language, "embed.field.created"),
 				Value:  created,
 				Inline: true,
 			},
 		},
 	}
+
+	if timer.SnoozeCount > 0 {
+		snoozed := tr(language, "snooze.count", timer.SnoozeCount)
+		if pushed := timer.SnoozedDue.Sub(timer.Due); pushed >= time.Minute {
+			snoozed += " (+" + humanizeDelay(pushed, language) + ")"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr(language, "embed.field.snoozed"),
+			Value:  snoozed,
+			Inline: true,
+		})
+	}
+	if timer.DeferredFrom != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr(language, "embed.field.deferred"),
+			Value:  tr(language, "quiet.deferred_from", timer.DeferredFrom.Unix()),
+			Inline: true,
+		})
+	}
+	if timer.RepingInterval > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr(language, "embed.field.important"),
+			Value:  describeAlerting(timer, language),
+			Inline: true,
+		})
+	}
+	if timer.AcknowledgedAt != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr(language, "embed.field.acknowledged"),
+			Value:  fmt.Sprintf("<t:%d:R>", timer.AcknowledgedAt.Unix()),
+			Inline: true,
+		})
+	}
+	if len(timer.Tags) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr(language, "embed.field.tags"),
+			Value:  formatTags(timer.Tags),
+			Inline: true,
+		})
+	}
+	if timer.ChainLength > 1 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   tr(language, "embed.field.chain"),
+			Value:  tr(language, "chain.position", timer.ChainPosition, timer.ChainLength),
+			Inline: true,
+		})
+	}
+	if len(timer.FollowUps) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  tr(language, "embed.field.follow_ups"),
+			Value: describeFollowUps(timer.FollowUps, language),
+		})
+	}
+
+	return embed
 }
 
-func formatTime(timeToFormat time.Time, includeDuration bool) string {
+func formatTime(timeToFormat time.Time, includeDuration bool, language string) string {
 	base := timeToFormat.In(time.Local).
 		Format("02/01/2006, 15:04:05")
 
 	if includeDuration {
 		// fmt.Sprintf("<t:%d:R>", timer.Due.Unix())
-		base += fmt.Sprintf("\n(%s)", humanize.Time(timeToFormat))
+		base += fmt.Sprintf("\n(%s)", humanizeTime(timeToFormat, language))
 	}
 
 	return base
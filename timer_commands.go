@@ -1,7 +1,10 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -11,7 +14,7 @@ import (
 func respondWithLog(session *discordgo.Session, interaction *discordgo.Interaction, response *discordgo.InteractionResponse, context string) {
 	err := session.InteractionRespond(interaction, response)
 	if err != nil {
-		fmt.Println("Error responding to interaction in", context+":", err)
+		slog.Error("Responding to interaction", "context", context, "error", err)
 	}
 }
 
@@ -23,7 +26,7 @@ func respondWithError(session *discordgo.Session, interaction *discordgo.Interac
 		},
 	}, context)
 	if err != nil {
-		fmt.Println("Error in", context+":", err)
+		slog.Error("Handling interaction", "context", context, "error", err)
 	}
 }
 
@@ -31,6 +34,8 @@ func handleTimer(session *discordgo.Session, interaction *discordgo.InteractionC
 	switch interaction.ApplicationCommandData().Options[0].Name {
 	case "create":
 		handleTimerCreate(session, interaction)
+	case "new":
+		handleTimerNew(session, interaction)
 	case "list":
 		handleTimerList(session, interaction)
 	case "delete":
@@ -39,6 +44,22 @@ func handleTimer(session *discordgo.Session, interaction *discordgo.InteractionC
 		handleTimerEdit(session, interaction)
 	case "snooze":
 		handleTimerSnooze(session, interaction)
+	case "clear-expired":
+		handleTimerClearExpired(session, interaction)
+	case "restore":
+		completeTimerRestore(session, interaction, normalizeTimerID(interaction.ApplicationCommandData().Options[0].Options[0].StringValue()), discordgo.InteractionResponseChannelMessageWithSource)
+	case "history":
+		handleTimerHistory(session, interaction)
+	case "search":
+		handleTimerSearch(session, interaction)
+	case "template":
+		handleTimerTemplate(session, interaction)
+	case "bulk-delete":
+		handleTimerBulkDelete(session, interaction)
+	case "bulk-snooze":
+		handleTimerBulkSnooze(session, interaction)
+	case "bulk-shift":
+		handleTimerBulkShift(session, interaction)
 	}
 }
 
@@ -48,22 +69,52 @@ func handleTimerAutocomplete(session *discordgo.Session, interaction *discordgo.
 		return
 	}
 
+	// Options of a subcommand group are one level deeper
 	subcommand := commandOptions[0]
-	if subcommand.Name != "delete" && subcommand.Name != "edit" && subcommand.Name != "snooze" {
-		return
+	if subcommand.Type == discordgo.ApplicationCommandOptionSubCommandGroup && len(subcommand.Options) > 0 {
+		subcommand = subcommand.Options[0]
 	}
 
-	var focusedValue string
+	var focused *discordgo.ApplicationCommandInteractionDataOption
 	for _, opt := range subcommand.Options {
-		if opt.Name == "id" && opt.Focused {
-			focusedValue = strings.ToLower(opt.StringValue())
+		if opt.Focused {
+			focused = opt
 			break
 		}
 	}
+	if focused == nil {
+		return
+	}
 
-	timers, err := getAllTimersForUser(getUserFromInteraction(interaction).ID, true)
+	switch focused.Name {
+	case "id":
+		handleTimerIDAutocomplete(session, interaction, normalizeTimerID(focused.StringValue()), subcommand.Name == "restore")
+	case "template", "name":
+		handleTemplateAutocomplete(session, interaction, focused.StringValue())
+	case "tag", "tags":
+		handleTagAutocomplete(session, interaction, focused.StringValue(), focused.Name == "tags")
+	case "time":
+		var favorites []string
+		if subcommand.Name == "snooze" {
+			favorites = getSnoozeSuggestions(getUserFromInteraction(interaction).ID)
+		}
+		handleTimeAutocomplete(session, interaction, focused.StringValue(), favorites)
+	}
+}
+
+// handleTimerIDAutocomplete suggests the active timers of the user, or the
+// deleted ones that can still be restored
+func handleTimerIDAutocomplete(session *discordgo.Session, interaction *discordgo.InteractionCreate, focusedValue string, deleted bool) {
+	userID := getUserFromInteraction(interaction).ID
+	var timers []*Timer
+	var err error
+	if deleted {
+		timers, err = getDeletedTimersForUser(userID)
+	} else {
+		timers, err = getAllTimersForUser(userID, true)
+	}
 	if err != nil {
-		fmt.Println("Error fetching timers for autocomplete:", err)
+		slog.Error("Fetching timers for autocomplete", "error", err)
 		return
 	}
 
@@ -84,14 +135,48 @@ func handleTimerAutocomplete(session *discordgo.Session, interaction *discordgo.
 		}
 	}
 
-	err = session.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+	respondWithAutocompleteChoices(session, interaction.Interaction, choices)
+}
+
+// handleTagAutocomplete suggests the tags the user already uses. With
+// multiple the input is a list and only its last tag is completed.
+func handleTagAutocomplete(session *discordgo.Session, interaction *discordgo.InteractionCreate, input string, multiple bool) {
+	tags, err := getTagsForUser(getUserFromInteraction(interaction).ID)
+	if err != nil {
+		slog.Error("Fetching tags for autocomplete", "error", err)
+		return
+	}
+
+	var suggestions []string
+	if multiple {
+		suggestions = suggestTags(input, tags)
+	} else {
+		suggestions = suggestTags(normalizeTag(input), tags)
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, 25)
+	for _, suggestion := range suggestions {
+		if len(suggestion) > 100 {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: suggestion, Value: suggestion})
+		if len(choices) == 25 {
+			break
+		}
+	}
+
+	respondWithAutocompleteChoices(session, interaction.Interaction, choices)
+}
+
+func respondWithAutocompleteChoices(session *discordgo.Session, interaction *discordgo.Interaction, choices []*discordgo.ApplicationCommandOptionChoice) {
+	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
 		Data: &discordgo.InteractionResponseData{
 			Choices: choices,
 		},
 	})
 	if err != nil {
-		fmt.Println("Error responding with autocomplete choices:", err)
+		slog.Error("Responding with autocomplete choices", "error", err)
 	}
 }
 
@@ -121,166 +206,548 @@ func buildTimerAutocompleteLabel(id string, message string) string {
 }
 
 func handleTimerCreate(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
-	options := interaction.ApplicationCommandData().Options[0].Options
-	message := options[0].StringValue()
-	timeStr := options[1].StringValue()
+	createTimerFromOptions(session, interaction, "", interaction.ApplicationCommandData().Options[0].Options)
+}
 
-	date, err := parseTime(timeStr)
-	if err != nil {
-		respondWithError(session, interaction.Interaction, "Invalid date format", "handleTimerCreate() error case parsing time", err)
+// createTimerFromOptions creates a timer from the options of /timer create,
+// /timer template use or the fields of the /timer new form. A template fills in whatever the options leave out.
+func createTimerFromOptions(session *discordgo.Session, interaction *discordgo.InteractionCreate, templateName string, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	language := getInteractionLanguage(interaction)
+
+	var message, timeStr, followUps string
+	var important *bool
+	var timerOptions TimerOptions
+	for _, opt := range options {
+		switch opt.Name {
+		case "message":
+			message = opt.StringValue()
+		case "time":
+			timeStr = opt.StringValue()
+		case "template":
+			templateName = opt.StringValue()
+		case "channel":
+			timerOptions.Channel = fmt.Sprint(opt.Value)
+		case "then":
+			followUps = opt.StringValue()
+		case "max_snoozes":
+			timerOptions.MaxSnoozes = int(opt.IntValue())
+		case "tags":
+			tags, err := parseTags(opt.StringValue())
+			if err != nil {
+				respondWithError(session, interaction.Interaction, tr(language, "error.invalid_tags", err), "createTimerFromOptions() invalid tags", err)
+				return
+			}
+			timerOptions.Tags = tags
+		case "important":
+			value := opt.BoolValue()
+			important = &value
+		case "reping_every":
+			timerOptions.RepingInterval = time.Duration(opt.IntValue()) * time.Minute
+		case "escalate_to":
+			timerOptions.EscalateTo = mentionOption(interaction, opt)
+		case "escalate_after":
+			timerOptions.EscalateAfter = time.Duration(opt.IntValue()) * time.Minute
+		case "private":
+			timerOptions.Private = opt.BoolValue()
+		}
+	}
+
+	if templateName != "" {
+		name, err := normalizeTemplateName(templateName)
+		var template *TimerTemplate
+		if err == nil {
+			template, err = resolveTemplate(getUserFromInteraction(interaction).ID, interaction.GuildID, name)
+		}
+		if err != nil {
+			respondWithError(session, interaction.Interaction, tr(language, "error.unknown_template", templateName), "createTimerFromOptions() unknown template", err)
+			return
+		}
+		message = cmp.Or(message, template.Message)
+		timeStr = cmp.Or(timeStr, template.Time)
+		followUps = cmp.Or(followUps, template.FollowUps)
+		if timerOptions.Tags == nil {
+			timerOptions.Tags = template.Tags
+		}
+		if important == nil {
+			important = &template.Important
+		}
+		timerOptions.Channel = cmp.Or(timerOptions.Channel, template.Channel)
+	}
+	// The channel of a template was picked elsewhere, maybe in another
+	// server or by someone else
+	if timerOptions.Channel != "" {
+		channel, ok := checkTargetChannel(session, interaction, timerOptions.Channel)
+		if !ok {
+			respondWithError(session, interaction.Interaction, tr(language, "error.invalid_channel", "<#"+timerOptions.Channel+">"), "createTimerFromOptions() invalid channel", nil)
+			return
+		}
+		timerOptions.Guild = channel.GuildID
+	}
+
+	if message == "" || timeStr == "" {
+		respondWithError(session, interaction.Interaction, tr(language, "error.message_and_time_required"), "createTimerFromOptions() missing message or time", nil)
+		return
+	}
+	if len(message) > config.Limits.MaxMessageLength {
+		respondWithError(session, interaction.Interaction, tr(language, "error.message_too_long", config.Limits.MaxMessageLength), "createTimerFromOptions() message too long", nil)
+		return
+	}
+	if followUps != "" {
+		parsed, err := parseFollowUps(followUps, getParseOptions(interaction), time.Now())
+		if err != nil {
+			respondWithError(session, interaction.Interaction, describeFollowUpError(err, language), "createTimerFromOptions() invalid follow-ups", err)
+			return
+		}
+		for _, followUp := range parsed {
+			if len(followUp.Message) > config.Limits.MaxMessageLength {
+				respondWithError(session, interaction.Interaction, tr(language, "error.message_too_long", config.Limits.MaxMessageLength), "createTimerFromOptions() follow-up message too long", nil)
+				return
+			}
+		}
+		timerOptions.FollowUps = parsed
+	}
+
+	// Each of the alerting options is enough to make a timer important
+	if (important != nil && *important || timerOptions.EscalateTo != "") && timerOptions.RepingInterval == 0 {
+		timerOptions.RepingInterval = defaultRepingInterval
+	}
+	if timerOptions.EscalateTo != "" && timerOptions.EscalateAfter == 0 {
+		timerOptions.EscalateAfter = defaultEscalateAfter
+	}
+
+	date, ok := resolveTimeInput(session, interaction, timeStr, &pendingTimeChoice{kind: "create", message: message, timerOptions: timerOptions})
+	if !ok {
 		return
 	}
 
+	completeTimerCreate(session, interaction, message, date, timerOptions, discordgo.InteractionResponseChannelMessageWithSource)
+}
+
+// completeTimerCreate creates the timer once its due time is known, either
+// directly from the command or after the user picked one of several
+// interpretations of the time
+func completeTimerCreate(session *discordgo.Session, interaction *discordgo.InteractionCreate, message string, date time.Time, timerOptions TimerOptions, responseType discordgo.InteractionResponseType) {
+	language := getInteractionLanguage(interaction)
+	user := getUserFromInteraction(interaction)
+	if config.Limits.MaxTimersPerUser > 0 {
+		count, err := countActiveTimersForUser(user.ID)
+		if err != nil {
+			respondWithError(session, interaction.Interaction, tr(language, "error.creating_timer"), "completeTimerCreate() error counting timers", err)
+			return
+		}
+		if count >= config.Limits.MaxTimersPerUser {
+			respondWithError(session, interaction.Interaction, tr(language, "error.timer_limit", config.Limits.MaxTimersPerUser), "completeTimerCreate() timer limit reached", nil)
+			return
+		}
+	}
+
 	id, err := newTimerID()
 	if err != nil {
-		respondWithError(session, interaction.Interaction, "Error creating timer ID", "handleTimerCreate() error case in creating timer id", err)
+		respondWithError(session, interaction.Interaction, tr(language, "error.creating_timer_id"), "completeTimerCreate() error case in creating timer id", err)
 		return
 	}
 
-	user := getUserFromInteraction(interaction)
-	timer, err := createTimer(id, message, user.ID, interaction.ChannelID, date)
+	timerOptions.Guild = cmp.Or(timerOptions.Guild, interaction.GuildID)
+	timer, err := createTimer(id, message, user.ID, cmp.Or(timerOptions.Channel, interaction.ChannelID), date, language, timerOptions)
 	if err != nil {
-		respondWithError(session, interaction.Interaction, "Error creating timer", "handleTimerCreate() error case in creating timer", err)
+		respondWithError(session, interaction.Interaction, tr(language, "error.creating_timer"), "completeTimerCreate() error case in creating timer", err)
 		return
 	}
+	recordTimerEvent(timer, TimerEventCreated, user.ID, map[string]string{"due": unixDetail(timer.Due)})
 
 	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Type: responseType,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: []*discordgo.MessageEmbed{
-				createTimerEmbed(timer, user, TimerEmbedTypeCreation),
+				createTimerEmbed(timer, user, TimerEmbedTypeCreation, language),
 			},
+			Components: []discordgo.MessageComponent{},
 		},
-	}, "handleTimerCreate() success case")
+	}, "completeTimerCreate() success case")
 }
 
+const (
+	ListScopeMine    = "mine"
+	ListScopeChannel = "channel"
+	ListScopeGuild   = "guild"
+)
+
 func handleTimerList(session *discordgo.Session, i *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(i)
+	user := getUserFromInteraction(i)
+
 	// Check if the show_expired option is provided
 	showExpired := false
-	options := i.ApplicationCommandData().Options[0].Options
-	if len(options) > 0 {
-		for _, opt := range options {
-			if opt.Name == "show_expired" {
-				showExpired = opt.BoolValue()
-				break
-			}
+	scope := ListScopeMine
+	filter := TimerFilter{}
+	for _, opt := range i.ApplicationCommandData().Options[0].Options {
+		switch opt.Name {
+		case "show_expired":
+			showExpired = opt.BoolValue()
+		case "tag":
+			filter.Tag = normalizeTag(opt.StringValue())
+		case "scope":
+			scope = opt.StringValue()
 		}
 	}
 
+	if scope != ListScopeMine && i.GuildID == "" {
+		respondWithError(session, i.Interaction, tr(language, "error.guild_only"), "handleTimerList() scope outside of guild", nil)
+		return
+	}
+	switch scope {
+	case ListScopeChannel:
+		filter.Channel = i.ChannelID
+	case ListScopeGuild:
+		filter.Guild = i.GuildID
+		filter.GuildChannels = guildChannelIDs(session, i.GuildID)
+	default:
+		filter.User = user.ID
+	}
+	if scope != ListScopeMine && !canSeePrivateTimers(i) {
+		filter.HidePrivate = true
+		filter.Viewer = user.ID
+	}
+	// The timers of others are only shown to the one who asked, the list can
+	// contain private timers the rest of the channel must not see
+	var flags discordgo.MessageFlags
+	if scope != ListScopeMine {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+
 	// Get timers based on the show_expired option
-	onlyActive := !showExpired
-	timers, err := getAllTimersForUser(i.Member.User.ID, onlyActive)
+	filter.OnlyActive = !showExpired
+	timers, err := listTimers(filter)
 	if err != nil {
-		respondWithError(session, i.Interaction, "Error getting timers", "handleTimerList() getting timers", err)
+		respondWithError(session, i.Interaction, tr(language, "error.getting_timers"), "handleTimerList() getting timers", err)
 		return
 	}
 
 	if len(timers) == 0 {
-		message := "You have no active timers."
+		message := tr(language, "list.no_active_timers")
 		if showExpired {
-			message = "You have no timers."
+			message = tr(language, "list.no_timers")
+		}
+		if scope != ListScopeMine {
+			message = tr(language, "list.empty."+scope)
 		}
 		respondWithLog(session, i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
 				Content: message,
+				Flags:   flags,
 			},
 		}, "handleTimerList() no timers")
 		return
 	}
 
-	title := "Active Timers"
+	title := tr(language, "list.active_title")
 	if showExpired {
-		title = "All Timers"
+		title = tr(language, "list.all_title")
+	}
+	if scope != ListScopeMine {
+		title += " " + tr(language, "list.scope."+scope)
+	}
+	if filter.Tag != "" {
+		title += " " + formatTags([]string{filter.Tag})
+	}
+
+	embed := createTimerListEmbed(title, timers, language)
+	if scope != ListScopeMine {
+		embed = createSharedTimerListEmbed(title, timers, language)
 	}
+	respondWithLog(session, i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  flags,
+		},
+	}, "handleTimerList() success case")
+}
+
+// canSeePrivateTimers is true for moderators, who may see the private timers
+// of everybody when listing a channel or guild
+func canSeePrivateTimers(interaction *discordgo.InteractionCreate) bool {
+	return interaction.Member != nil && interaction.Member.Permissions&discordgo.PermissionManageMessages != 0
+}
+
+// guildChannelIDs returns the channels of a guild the bot knows about
+func guildChannelIDs(session *discordgo.Session, guildID string) []string {
+	guild, err := session.State.Guild(guildID)
+	if err != nil {
+		slog.Error("Getting guild from state", "error", err)
+		return nil
+	}
+	channels := make([]string, 0, len(guild.Channels)+len(guild.Threads))
+	for _, channel := range guild.Channels {
+		channels = append(channels, channel.ID)
+	}
+	for _, thread := range guild.Threads {
+		channels = append(channels, thread.ID)
+	}
+	return channels
+}
+
+// handleTimerSearch lists the timers of the user whose message contains the query
+func handleTimerSearch(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+
+	filter := TimerFilter{User: getUserFromInteraction(interaction).ID, OnlyActive: true}
+	for _, opt := range interaction.ApplicationCommandData().Options[0].Options {
+		switch opt.Name {
+		case "query":
+			filter.Query = opt.StringValue()
+		case "tag":
+			filter.Tag = normalizeTag(opt.StringValue())
+		case "show_expired":
+			filter.OnlyActive = !opt.BoolValue()
+		}
+	}
+
+	timers, err := listTimers(filter)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.getting_timers"), "handleTimerSearch() getting timers", err)
+		return
+	}
+
+	if len(timers) == 0 {
+		respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: tr(language, "search.no_results", filter.Query),
+			},
+		}, "handleTimerSearch() no timers")
+		return
+	}
+
+	title := tr(language, "search.title", filter.Query)
+	if filter.Tag != "" {
+		title += " " + formatTags([]string{filter.Tag})
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{createTimerListEmbed(title, timers, language)},
+		},
+	}, "handleTimerSearch() success case")
+}
+
+// maxListedTimers is the number of fields an embed can have
+const maxListedTimers = 25
+
+func createTimerListEmbed(title string, timers []*Timer, language string) *discordgo.MessageEmbed {
+	return buildTimerListEmbed(title, timers, false, language)
+}
+
+// createSharedTimerListEmbed lists the timers of several users, mentioning
+// the owner of each
+func createSharedTimerListEmbed(title string, timers []*Timer, language string) *discordgo.MessageEmbed {
+	return buildTimerListEmbed(title, timers, true, language)
+}
+
+func buildTimerListEmbed(title string, timers []*Timer, showOwner bool, language string) *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{
 		Title: title,
 		Color: 0x3c1984,
 	}
 
 	for _, timer := range timers {
+		if len(embed.Fields) == maxListedTimers {
+			embed.Footer = &discordgo.MessageEmbedFooter{Text: tr(language, "list.more", len(timers)-maxListedTimers)}
+			break
+		}
+		value := tr(language, "list.entry", timer.Message, timer.SnoozedDue.Unix())
+		if showOwner {
+			value += "\n" + tr(language, "list.owner", timer.User)
+			if timer.Private {
+				value += " · " + tr(language, "list.private")
+			}
+		}
+		if len(timer.Tags) > 0 {
+			value += "\n" + formatTags(timer.Tags)
+		}
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:  timer.ID,
-			Value: fmt.Sprintf("%s - Due: <t:%d:R>", timer.Message, timer.SnoozedDue.Unix()),
+			Value: value,
 		})
 	}
+	return embed
+}
 
-	respondWithLog(session, i.Interaction, &discordgo.InteractionResponse{
+func handleTimerDelete(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+	options := interaction.ApplicationCommandData().Options[0].Options
+	timerID := normalizeTimerID(options[0].StringValue())
+
+	timer, err := getOwnedTimer(session, interaction, timerID, "handleTimerDelete()")
+	if err != nil {
+		return
+	}
+	user := getUserFromInteraction(interaction)
+
+	err = deleteTimer(timerID)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.deleting_timer"), "handleTimerDelete() error deleting timer", err)
+		return
+	}
+	recordTimerEvent(timer, TimerEventDeleted, user.ID, nil)
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{embed},
+			Embeds: []*discordgo.MessageEmbed{
+				createTimerEmbed(timer, user, TimerEmbedTypeDeletion, language),
+			},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label:    tr(language, "button.undo"),
+							Style:    discordgo.SecondaryButton,
+							CustomID: "timer_restore:" + timer.ID,
+						},
+					},
+				},
+			},
 		},
-	}, "handleTimerList() success case")
+	}, "handleTimerDelete() success case")
 }
 
-func handleTimerDelete(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
-	options := interaction.ApplicationCommandData().Options[0].Options
-	timerID := options[0].StringValue()
+// handleTimerClearExpired deletes the shown timers of the user, they can be
+// restored like any other deleted timer until they are purged
+func handleTimerClearExpired(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+	user := getUserFromInteraction(interaction)
 
-	timer, err := getTimerByID(timerID)
+	timers, err := clearExpiredTimers(user.ID)
 	if err != nil {
-		respondWithError(session, interaction.Interaction, "Invalid timer ID", "handleTimerDelete() invalid timer id", err)
+		respondWithError(session, interaction.Interaction, tr(language, "error.deleting_timer"), "handleTimerClearExpired() error clearing timers", err)
 		return
 	}
+	for _, timer := range timers {
+		recordTimerEvent(timer, TimerEventDeleted, user.ID, nil)
+	}
+
+	content := tr(language, "clear_expired.none")
+	if len(timers) > 0 {
+		content = tr(language, "clear_expired.done", len(timers), humanizeDelay(config.Retention.DeletedTimers, language))
+	}
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}, "handleTimerClearExpired() success case")
+}
 
+// completeTimerRestore brings back a deleted timer, either from /timer restore
+// or from the undo button of the deletion message
+func completeTimerRestore(session *discordgo.Session, interaction *discordgo.InteractionCreate, timerID string, responseType discordgo.InteractionResponseType) {
+	language := getInteractionLanguage(interaction)
 	user := getUserFromInteraction(interaction)
 
+	timer, err := getDeletedTimerByID(timerID)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.invalid_deleted_timer_id"), "completeTimerRestore() invalid timer id", err)
+		return
+	}
 	if timer.User != user.ID {
-		respondWithError(session, interaction.Interaction, "You do not own this timer", "handleTimerDelete() not owner", err)
+		respondWithError(session, interaction.Interaction, tr(language, "error.not_owner"), "completeTimerRestore() not owner", errNotOwner)
 		return
 	}
 
-	err = deleteTimer(timerID)
+	err = restoreTimer(timerID)
 	if err != nil {
-		respondWithError(session, interaction.Interaction, "Error deleting timer", "handleTimerDelete() error deleting timer", err)
+		respondWithError(session, interaction.Interaction, tr(language, "error.restoring_timer"), "completeTimerRestore() error restoring timer", err)
 		return
 	}
+	recordTimerEvent(timer, TimerEventRestored, user.ID, nil)
 
 	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Type: responseType,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: []*discordgo.MessageEmbed{
-				createTimerEmbed(timer, user, TimerEmbedTypeDeletion),
+				createTimerEmbed(timer, user, TimerEmbedTypeRestored, language),
 			},
+			Components: []discordgo.MessageComponent{},
 		},
-	}, "handleTimerDelete() success case")
+	}, "completeTimerRestore() success case")
 }
 
 func handleTimerEdit(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
 	options := interaction.ApplicationCommandData().Options[0].Options
-	timerID := options[0].StringValue()
+	timerID := normalizeTimerID(options[0].StringValue())
 
-	timer, err := getTimerByID(timerID)
+	timer, err := getOwnedTimer(session, interaction, timerID, "handleTimerEdit()")
 	if err != nil {
-		respondWithError(session, interaction.Interaction, "Invalid timer ID", "handleTimerEdit() invalid timer id", err)
 		return
 	}
 
-	user := getUserFromInteraction(interaction)
-
-	if timer.User != user.ID {
-		respondWithError(session, interaction.Interaction, "You do not own this timer", "handleTimerEdit() not owner", err)
+	// Without anything to change the timer is edited in a form instead
+	if len(options) == 1 {
+		respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: buildTimerModal("timer_modal:edit:"+timer.ID, tr(language, "modal.title.edit", timer.ID), timerModalValuesOf(timer), false, language),
+		}, "handleTimerEdit() modal")
 		return
 	}
 
+	editTimerFromOptions(session, interaction, timerID, options[1:])
+}
+
+// editTimerFromOptions changes a timer by the options of /timer edit or the
+// fields of the edit form that were changed
+func editTimerFromOptions(session *discordgo.Session, interaction *discordgo.InteractionCreate, timerID string, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	language := getInteractionLanguage(interaction)
+
 	var newMessage *string
-	var newTime *time.Time
+	var newTags *[]string
+	var timeStr string
 
-	for _, opt := range options[1:] {
+	for _, opt := range options {
 		switch opt.Name {
 		case "message":
 			val := opt.StringValue()
+			if len(val) > config.Limits.MaxMessageLength {
+				respondWithError(session, interaction.Interaction, tr(language, "error.message_too_long", config.Limits.MaxMessageLength), "editTimerFromOptions() message too long", nil)
+				return
+			}
 			newMessage = &val
 		case "time":
-			date, err := parseTime(opt.StringValue())
+			timeStr = opt.StringValue()
+		case "tags":
+			tags, err := parseTags(opt.StringValue())
 			if err != nil {
-				respondWithError(session, interaction.Interaction, "Invalid date format", "handleTimerEdit() invalid date format", err)
+				respondWithError(session, interaction.Interaction, tr(language, "error.invalid_tags", err), "editTimerFromOptions() invalid tags", err)
 				return
 			}
-			newTime = &date
+			newTags = &tags
 		}
 	}
 
+	var newTime *time.Time
+	if timeStr != "" {
+		date, ok := resolveTimeInput(session, interaction, timeStr, &pendingTimeChoice{kind: "edit", timerID: timerID, newMessage: newMessage, newTags: newTags})
+		if !ok {
+			return
+		}
+		newTime = &date
+	}
+
+	completeTimerEdit(session, interaction, timerID, newMessage, newTags, newTime, discordgo.InteractionResponseChannelMessageWithSource)
+}
+
+func completeTimerEdit(session *discordgo.Session, interaction *discordgo.InteractionCreate, timerID string, newMessage *string, newTags *[]string, newTime *time.Time, responseType discordgo.InteractionResponseType) {
+	language := getInteractionLanguage(interaction)
+	timer, err := getOwnedTimer(session, interaction, timerID, "completeTimerEdit()")
+	if err != nil {
+		return
+	}
+	old := *timer
+
 	if newMessage != nil {
 		timer.Message = *newMessage
 	}
@@ -288,67 +755,255 @@ func handleTimerEdit(session *discordgo.Session, interaction *discordgo.Interact
 		timer.Due = *newTime
 		timer.SnoozedDue = *newTime
 	}
+	if newTags != nil {
+		timer.Tags = *newTags
+	}
 
 	err = updateTimer(timer)
+	if err == nil && newTags != nil {
+		err = setTimerTags(timer.ID, timer.Tags)
+	}
 	if err != nil {
-		respondWithError(session, interaction.Interaction, "Error updating timer", "handleTimerEdit() error updating timer", err)
+		respondWithError(session, interaction.Interaction, tr(language, "error.updating_timer"), "completeTimerEdit() error updating timer", err)
 		return
 	}
+	recordTimerEvent(timer, TimerEventEdited, getUserFromInteraction(interaction).ID, editDetails(&old, timer))
 
 	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Type: responseType,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: []*discordgo.MessageEmbed{
-				createTimerEmbed(timer, getUserFromInteraction(interaction), TimerEmbedTypeEdit),
+				createTimerEmbed(timer, getUserFromInteraction(interaction), TimerEmbedTypeEdit, language),
 			},
+			Components: []discordgo.MessageComponent{},
 		},
-	}, "handleTimerEdit() success case")
+	}, "completeTimerEdit() success case")
 }
 
 func handleTimerSnooze(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
 	options := interaction.ApplicationCommandData().Options[0].Options
-	timerID := options[0].StringValue()
+	timerID := normalizeTimerID(options[0].StringValue())
 	timeStr := options[1].StringValue()
 
-	timer, err := getTimerByID(timerID)
+	fromDue := false
+	for _, opt := range options[2:] {
+		if opt.Name == "relative_to" {
+			fromDue = opt.StringValue() == "due"
+		}
+	}
+
+	timer, err := getOwnedTimer(session, interaction, timerID, "handleTimerSnooze()")
 	if err != nil {
-		respondWithError(session, interaction.Interaction, "Invalid timer ID", "handleTimerSnooze() invalid timer id", err)
+		return
+	}
+	// Only a duration can be counted from the due time, a point in time is
+	// the same from anywhere
+	if fromDue && !isDuration(timeStr) {
+		language := getInteractionLanguage(interaction)
+		respondWithError(session, interaction.Interaction, tr(language, "error.snooze_from_due_duration"), "handleTimerSnooze() not a duration", nil)
+		return
+	}
+	if !checkSnoozeLimit(session, interaction, timer, discordgo.InteractionResponseChannelMessageWithSource) {
 		return
 	}
 
-	user := getUserFromInteraction(interaction)
-
-	if timer.User != user.ID {
-		respondWithError(session, interaction.Interaction, "You do not own this timer", "handleTimerSnooze() not owner", err)
+	date, ok := resolveTimeInput(session, interaction, timeStr, &pendingTimeChoice{kind: "snooze", timerID: timerID, snoozeFromDue: fromDue})
+	if !ok {
 		return
 	}
 
-	date, err := parseTime(timeStr)
+	completeTimerSnooze(session, interaction, timerID, date, fromDue, discordgo.InteractionResponseChannelMessageWithSource)
+}
+
+// completeTimerSnooze snoozes the timer to date. With fromDue the snooze is
+// counted from the original due time of the timer instead of from now.
+func completeTimerSnooze(session *discordgo.Session, interaction *discordgo.InteractionCreate, timerID string, date time.Time, fromDue bool, responseType discordgo.InteractionResponseType) {
+	language := getInteractionLanguage(interaction)
+	timer, err := getOwnedTimer(session, interaction, timerID, "completeTimerSnooze()")
 	if err != nil {
-		respondWithError(session, interaction.Interaction, "Invalid date format", "handleTimerSnooze() invalid date format", err)
 		return
 	}
+	if !checkSnoozeLimit(session, interaction, timer, responseType) {
+		return
+	}
+
+	if fromDue {
+		now := time.Now()
+		date = timer.Due.Add(date.Sub(now).Round(time.Minute))
+		if !date.After(now) {
+			respondWithError(session, interaction.Interaction, tr(language, "error.snooze_from_due_in_past", formatTime(date, false, language)), "completeTimerSnooze() time in past", nil)
+			return
+		}
+	}
 
-	err = snoozeTimer(timerID, date)
+	user := getUserFromInteraction(interaction)
+	err = snoozeTimer(timerID, date, user.ID)
 	if err != nil {
-		respondWithError(session, interaction.Interaction, "Error snoozing timer", "handleTimerSnooze() error snoozing timer", err)
+		respondWithError(session, interaction.Interaction, tr(language, "error.snoozing_timer"), "completeTimerSnooze() error snoozing timer", err)
 		return
 	}
+	recordTimerEvent(timer, TimerEventSnoozed, user.ID, map[string]string{"oldDue": unixDetail(timer.SnoozedDue), "newDue": unixDetail(date)})
 
 	snoozedTimer, err := getTimerByID(timerID)
 	if err != nil {
-		respondWithError(session, interaction.Interaction, "Error getting snoozed timer", "handleTimerSnooze() error getting snoozed timer", err)
+		respondWithError(session, interaction.Interaction, tr(language, "error.getting_timer"), "completeTimerSnooze() error getting snoozed timer", err)
 		return
 	}
 
+	embed := createTimerEmbed(snoozedTimer, user, TimerEmbedTypeSnooze, language)
+	snoozes, err := getSnoozeHistory(timerID)
+	if err != nil {
+		slog.Error("Getting snooze history in completeTimerSnooze()", "error", err)
+	} else if len(snoozes) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  tr(language, "embed.field.snooze_history"),
+			Value: describeSnoozes(snoozes, language),
+		})
+	}
+
 	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Type: responseType,
 		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: []discordgo.MessageComponent{},
+		},
+	}, "completeTimerSnooze() success case")
+}
+
+// checkSnoozeLimit makes sure the timer may be snoozed once more. Otherwise
+// the timer escalates or expires, depending on the guild's setting, and the
+// user is told so. A timer escalates to its backup if it has one, else the
+// owner is reminded to take care of it.
+func checkSnoozeLimit(session *discordgo.Session, interaction *discordgo.InteractionCreate, timer *Timer, responseType discordgo.InteractionResponseType) bool {
+	limit := getSnoozeLimit(timer)
+	if limit == 0 || timer.SnoozeCount < limit {
+		return true
+	}
+
+	language := getInteractionLanguage(interaction)
+	owner := getUserFromInteraction(interaction)
+	embedType := TimerEmbedTypeEscalated
+	content := tr(language, "snooze.limit.escalated", owner.Mention(), limit)
+
+	switch {
+	case getSnoozeLimitAction(timer) == SnoozeLimitExpire:
+		err := markTimerAsShown(timer.ID)
+		if err != nil {
+			respondWithError(session, interaction.Interaction, tr(language, "error.updating_timer"), "checkSnoozeLimit() error expiring timer", err)
+			return false
+		}
+		recordTimerEvent(timer, TimerEventExpired, owner.ID, nil)
+		embedType = TimerEmbedTypeExpired
+		content = tr(language, "snooze.limit.expired", limit)
+	case timer.EscalateTo != "":
+		if timer.EscalatedAt == nil {
+			escalateTimer(session, timer, time.Now())
+		}
+		content = tr(language, "snooze.limit.escalated_to", limit, timer.EscalateTo)
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
 			Embeds: []*discordgo.MessageEmbed{
-				createTimerEmbed(snoozedTimer, getUserFromInteraction(interaction), TimerEmbedTypeSnooze),
+				createTimerEmbed(timer, owner, embedType, language),
 			},
+			Components: []discordgo.MessageComponent{},
 		},
-	}, "handleTimerSnooze() success case")
+	}, "checkSnoozeLimit() limit reached")
+	return false
+}
+
+// handleTimerHistory shows the timeline of a timer. The history is looked up
+// by owner instead of through getOwnedTimer so it works for deleted timers.
+func handleTimerHistory(session *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	language := getInteractionLanguage(interaction)
+	timerID := normalizeTimerID(interaction.ApplicationCommandData().Options[0].Options[0].StringValue())
+
+	events, err := getTimerEvents(timerID, getUserFromInteraction(interaction).ID)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.getting_history"), "handleTimerHistory() getting events", err)
+		return
+	}
+	if len(events) == 0 {
+		respondWithError(session, interaction.Interaction, tr(language, "error.no_history"), "handleTimerHistory() no events", nil)
+		return
+	}
+
+	respondWithLog(session, interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{createTimerHistoryEmbed(timerID, events, language)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}, "handleTimerHistory() success case")
+}
+
+// checkTargetChannel makes sure a timer may be sent to another channel: it
+// has to be in the guild of the interaction and the user has to be allowed
+// to send messages there
+func checkTargetChannel(session *discordgo.Session, interaction *discordgo.InteractionCreate, channelID string) (*discordgo.Channel, bool) {
+	if interaction.GuildID == "" {
+		return nil, false
+	}
+	channel, err := session.State.Channel(channelID)
+	if err != nil {
+		channel, err = session.Channel(channelID)
+	}
+	if err != nil || channel.GuildID != interaction.GuildID {
+		return nil, false
+	}
+
+	required := int64(discordgo.PermissionViewChannel | discordgo.PermissionSendMessages)
+	permissions, err := session.UserChannelPermissions(getUserFromInteraction(interaction).ID, channel.ID)
+	if err != nil {
+		slog.Error("Getting channel permissions", "error", err)
+		return nil, false
+	}
+	return channel, permissions&required == required
+}
+
+// getOwnedTimer loads a timer and makes sure it belongs to the user of the
+// interaction. On failure the error has already been reported to the user.
+func getOwnedTimer(session *discordgo.Session, interaction *discordgo.InteractionCreate, timerID string, context string) (*Timer, error) {
+	language := getInteractionLanguage(interaction)
+	timer, err := getTimerByID(timerID)
+	if err != nil {
+		respondWithError(session, interaction.Interaction, tr(language, "error.invalid_timer_id"), context+" invalid timer id", err)
+		return nil, err
+	}
+
+	if timer.User != getUserFromInteraction(interaction).ID {
+		err = errNotOwner
+		respondWithError(session, interaction.Interaction, tr(language, "error.not_owner"), context+" not owner", err)
+		return nil, err
+	}
+
+	// The timers of pomodoro phases belong to their session, changing them
+	// directly would leave the session waiting for a phase that never ends
+	if timer.PomodoroID != "" {
+		err = errPomodoroTimer
+		respondWithError(session, interaction.Interaction, tr(language, "error.pomodoro_timer"), context+" pomodoro timer", nil)
+		return nil, err
+	}
+
+	return timer, nil
+}
+
+var (
+	errNotOwner      = errors.New("timer is owned by another user")
+	errPomodoroTimer = errors.New("timer belongs to a pomodoro session")
+)
+
+// mentionOption turns the value of a mentionable option into a mention of
+// the user or role
+func mentionOption(interaction *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) string {
+	id := fmt.Sprint(opt.Value)
+	if resolved := interaction.ApplicationCommandData().Resolved; resolved != nil && resolved.Roles[id] != nil {
+		return "<@&" + id + ">"
+	}
+	return "<@" + id + ">"
 }
 
 func getUserFromInteraction(interaction *discordgo.InteractionCreate) *discordgo.User {